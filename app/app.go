@@ -9,23 +9,29 @@ import (
 	gui "github.com/grupawp/warships-gui/v2"
 	"github.com/micmonay/keybd_event"
 	"log"
+	"main/bot"
 	"main/client"
+	"main/game"
+	"main/replay"
 	"runtime"
-	"strconv"
 	"strings"
 	"time"
 )
 
 const (
-	waitingTime   = time.Second / 3
-	countdownTime = 30
-	hitRes        = "hit"
-	missRes       = "miss"
-	sunkRes       = "sunk"
-	blankRes      = ""
-	yBoards       = 8
-	xPBoard       = 1
-	xOBoard       = 100
+	waitingTime    = time.Second / 3
+	abandonTimeout = 5 * time.Second
+	hitRes         = "hit"
+	missRes        = "miss"
+	sunkRes        = "sunk"
+	blankRes       = ""
+	yBoards        = 8
+	xPBoard        = 1
+	xOBoard        = 100
+	xFleetBoard    = 35
+	yFleetBoard    = 1
+	yFleetHints    = 24
+	fleetHintLines = 6
 )
 
 var (
@@ -41,19 +47,26 @@ func (a *App) Start() {
 			log.Fatalf("app Start() 4, a.makeUI(); %v", err)
 		}
 		a.Ui = ui
-		a.Client = client.NewClient()
-		game, err := a.getDetails(a.Client, ctxFleet, cancelCtxFleet)
-		if err != nil {
-			log.Fatalf("app Start() 1, a.getDetails(); %v", err)
+		a.Client = a.newClient()
+		a.Session = nil
+		var newGame client.Game
+		resumed := a.resumeSession(ctx)
+		if !resumed {
+			newGame, err = a.getDetails(a.Client, ctx, ctxFleet, cancelCtxFleet)
+			if err != nil {
+				log.Fatalf("app Start() 1, a.getDetails(); %v", err)
+			}
+		} else {
+			cancelCtxFleet()
 		}
 
-		if a.Client.Token != "" {
-			a.Status, err = a.Client.GetStatus()
+		if a.Client.GetToken() != "" {
+			a.Status, err = a.Client.GetStatus(ctx)
 			if err != nil {
 				log.Fatalf("app Start() 2, client.GetStatus(); %v", err)
 			}
 
-			gameDesc, err := a.Client.GetDescription()
+			gameDesc, err := a.Client.GetDescription(ctx)
 			if err != nil {
 				log.Fatalf("app Start() 3, client.GetDescription(); %v", err)
 			}
@@ -64,31 +77,33 @@ func (a *App) Start() {
 
 			guiBattle := a.buildBattlefield(ui)
 
-			ans, err := a.Client.GetBoard()
+			ans, err := a.Client.GetBoard(ctx)
 			if err != nil {
 				log.Fatalf("app Start() 5, client.GetBoard(); %v", err)
 			}
 			layout := ans.Board
 
 			a.PlayerBoard = layout
+			a.saveServerLayout(newGame, layout)
 
-			var mapped [][]int
-			mapped, err = a.mappingChars(layout)
+			coords, err := game.ParseCoords(layout)
 			if err != nil {
-				log.Fatalf("app Start() 6, a.mappingChars(); %v", err)
-			}
-			states := [10][10]gui.State{}
-			for _, i := range mapped {
-				states[i[0]][i[1]-1] = gui.Ship
+				log.Fatalf("app Start() 6, game.ParseCoords(); %v", err)
 			}
+			states := toStates(game.NewBoard(coords))
 			guiBattle.PlayerBoardStates = states
 			guiBattle.PlayerBoard.SetStates(states)
+			if resumed {
+				a.restoreBattle(guiBattle)
+			}
+			a.updateSession()
+			a.startRecording(newGame.WPBot)
 
 			go a.startBattle(guiBattle, ctx, cancelCtx)
 
 			guiBattle.Ui.Start(ctx, nil)
 
-			fmt.Println(game)
+			fmt.Println(newGame)
 		} else {
 			cancelCtx()
 			break
@@ -96,254 +111,306 @@ func (a *App) Start() {
 	}
 }
 
-func (a *App) timerUpdate(guiB *GuiBattle, ctx context.Context, cancelCtx context.CancelFunc) {
-	var winner string
-	quitChan := make(chan bool)
-	if a.Client.Token == "" {
-		return
-	}
-	for a.Status.GameStatus != "ended" {
-		select {
-		default:
-			var err error
-			if a.Client.Token != "" {
-				a.Status, err = a.Client.GetStatus()
-				if err != nil {
-					log.Fatalf("app timerUpdate() 7, client.GetStatus(); %v", err)
-				}
-				guiB.Timer.SetText(fmt.Sprintf("Time: %v", a.Status.Timer))
-			} else {
-				cancelCtx()
-			}
-		case <-ctx.Done():
-			if a.Client.Token != "" {
-				err := a.Client.Abandon()
-				if err != nil {
-					log.Fatalf("app timerUpdate() 8, client.Abandon(); %v", err)
-				}
-			}
-			quitChan <- true
-		}
-	}
-	winner = a.Nick
-	cfg := gui.TextConfig{BgColor: gui.Green, FgColor: gui.White}
-	if a.Status.LastGameStatus == "lose" {
-		winner = a.TargetNick
-		cfg = gui.TextConfig{BgColor: gui.Red}
-	}
-	guiB.Ui.Remove(guiB.PlayerNick)
-	guiB.Ui.Remove(guiB.PlayerBoard)
-	guiB.Ui.Remove(guiB.PlayerAccuracy)
-	guiB.Ui.Remove(guiB.OpponentNick)
-	guiB.Ui.Remove(guiB.OpponentBoard)
-	guiB.Ui.Remove(guiB.ShotResult)
-	guiB.Ui.Remove(guiB.OppShotResult)
-	guiB.Ui.Remove(guiB.ShouldFire)
-	guiB.Ui.Remove(guiB.Timer)
-	guiB.Ui.Remove(guiB.OpponentAccuracy)
-	guiB.Ui.Draw(gui.NewText(xPBoard, yBoards, fmt.Sprintf("You %s!", a.Status.LastGameStatus), &cfg))
-	guiB.Ui.Draw(gui.NewText(xPBoard, yBoards+3, fmt.Sprintf("Winner: %s", winner), &cfg))
-	guiB.Exit.SetText("To start a new game press CTRL+C")
-	guiB.Ui.Log(fmt.Sprintf("Winner: %s", winner))
+// abandon leaves the current game. It uses its own context because it is
+// called after the battle context has been cancelled.
+func (a *App) abandon() error {
+	ctx, cancel := context.WithTimeout(context.Background(), abandonTimeout)
+	defer cancel()
+	a.clearSession()
+	return a.Client.Abandon(ctx)
 }
-func (a *App) getAdjacentCoordinates(coord string) []string {
-	row := int(coord[1] - '1')
-	col := int(coord[0] - 'A')
-	directions := [8][2]int{
-		{-1, -1}, {-1, 0}, {-1, 1},
-		{0, -1}, {0, 1},
-		{1, -1}, {1, 0}, {1, 1},
+
+// newClient returns a fresh API client for the next game, using NewAPI when set.
+func (a *App) newClient() client.GameAPI {
+	if a.NewAPI != nil {
+		return a.NewAPI()
 	}
+	return client.NewClient()
+}
 
-	adjacentCoords := make([]string, 0)
+type errAction int
 
-	for _, dir := range directions {
-		newRow := row + dir[0]
-		newCol := col + dir[1]
-		if newRow >= 0 && newRow < 10 && newCol >= 0 && newCol < 10 {
-			newCoord := fmt.Sprintf("%c%d", 'A'+newCol, newRow+1)
-			adjacentCoords = append(adjacentCoords, newCoord)
-		}
-	}
+const (
+	errFatal errAction = iota
+	errRetry
+	errEnded
+)
 
-	return adjacentCoords
+// battleError decides how the battle loops react to a failed request:
+// retry on rate limits and out of turn shots, stop when the game is gone.
+func (a *App) battleError(guiB *GuiBattle, err error) errAction {
+	switch {
+	case errors.Is(err, client.ErrNotYourTurn):
+		guiB.ShouldFire.SetText("It's not your turn!")
+		guiB.ShouldFire.SetFgColor(gui.Red)
+		return errRetry
+	case errors.Is(err, client.ErrRateLimited):
+		time.Sleep(waitingTime)
+		return errRetry
+	case gameGone(err):
+		return errEnded
+	}
+	return errFatal
 }
 
-func (a *App) markAdjacentMisses(guiB *GuiBattle, hitShots []string, char string) {
-	adjacentCoords := make(map[string]bool)
+// gameGone reports whether err means the server no longer knows our game.
+func gameGone(err error) bool {
+	return errors.Is(err, client.ErrUnauthorized) || errors.Is(err, client.ErrGameNotFound)
+}
 
-	// Iterate over each hitShot and collect all adjacent coordinates
-	for _, shot := range hitShots {
-		coords := a.getAdjacentCoordinates(shot)
-		for _, coord := range coords {
-			adjacentCoords[coord] = true
-		}
+// pollStatus fetches the game status and keeps it as a.Status, which both
+// loops of the battle screen read.
+func (a *App) pollStatus(ctx context.Context) (client.StatusResponse, error) {
+	status, err := a.Client.GetStatus(ctx)
+	if err != nil {
+		return status, err
 	}
+	a.mu.Lock()
+	a.Status = status
+	a.mu.Unlock()
+	return status, nil
+}
 
-	// Mark adjacent tiles as Miss
-	for coord := range adjacentCoords {
-		row := int(coord[1] - '1')
-		col := int(coord[0] - 'A')
+// status returns the last status seen by either battle loop.
+func (a *App) status() client.StatusResponse {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.Status
+}
 
-		if guiB.OpponentBoardStates[row][col] != gui.Hit {
-			guiB.OpponentBoardStates[row][col] = gui.Miss
+// timerUpdate keeps the turn timer current while startBattle waits for
+// shots. Leaving the game is up to startBattle.
+func (a *App) timerUpdate(guiB *GuiBattle, ctx context.Context, cancelCtx context.CancelFunc) {
+	if a.Client.GetToken() == "" {
+		return
+	}
+	status := a.status()
+	for status.GameStatus != "ended" {
+		select {
+		default:
+			if a.Client.GetToken() == "" {
+				cancelCtx()
+				continue
+			}
+			var err error
+			status, err = a.pollStatus(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				if gameGone(err) {
+					a.finishBattle(guiB, err)
+					return
+				}
+				if errors.Is(err, client.ErrRateLimited) {
+					time.Sleep(waitingTime)
+					continue
+				}
+				log.Fatalf("app timerUpdate() 7, client.GetStatus(); %v", err)
+			}
+			guiB.Timer.SetText(fmt.Sprintf("Time: %v", status.Timer))
+		case <-ctx.Done():
+			return
 		}
 	}
+	a.finishBattle(guiB, nil)
 }
-
 func (a *App) startBattle(guiB *GuiBattle, ctx context.Context, cancelCtx context.CancelFunc) {
-	var err error
 	shots := make([]string, 0)
 	hitShots := make([]string, 0)
 	oppHitShots := make([]string, 0)
-	quitChan := make(chan bool)
 	go a.timerUpdate(guiB, ctx, cancelCtx)
-	a.Status, err = a.Client.GetStatus()
+	status, err := a.pollStatus(ctx)
 	if err != nil {
+		if a.battleError(guiB, err) == errEnded {
+			a.finishBattle(guiB, err)
+			return
+		}
 		log.Fatalf("app startBattle() 9, client.GetStatus(); %v", err)
 	}
-	board, err := a.Client.GetBoard()
+	board, err := a.Client.GetBoard(ctx)
 	if err != nil {
+		if a.battleError(guiB, err) == errEnded {
+			a.finishBattle(guiB, err)
+			return
+		}
 		log.Fatalf("app startBattle() 10, client.GetBoard(); %v", err)
 	}
 	a.PlayerBoard = board.Board
-	for a.Status.GameStatus != "ended" {
+	if a.Session != nil {
+		for _, shot := range a.Session.Shots {
+			shots = append(shots, shot.Coord)
+			if shot.Result == hitRes || shot.Result == sunkRes {
+				hitShots = append(hitShots, shot.Coord)
+			}
+		}
+	}
+	for status.GameStatus != "ended" {
 		select {
 		default:
 			time.Sleep(waitingTime)
-			a.Status, err = a.Client.GetStatus()
+			status, err = a.pollStatus(ctx)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				switch a.battleError(guiB, err) {
+				case errRetry:
+					continue
+				case errEnded:
+					a.finishBattle(guiB, err)
+					return
+				}
 				log.Fatalf("app startBattle() 11, client.GetStatus(); %v", err)
 			}
-			if a.Status.ShouldFire {
-				for _, shot := range a.Status.OppShots {
+			if status.ShouldFire {
+				for _, shot := range status.OppShots {
 					res := ""
-					x, y, err := a.stringCoordToInt(shot)
+					c, err := game.ParseCoord(shot)
 					if err != nil {
-						log.Fatalf("app startBattle() 12, a.stringCoordToInt(); %v", err)
+						log.Fatalf("app startBattle() 12, game.ParseCoord(); %v", err)
 					}
-					if a.contains(shot, a.PlayerBoard) && guiB.PlayerBoardStates[x][y-1] != gui.Hit {
-						guiB.PlayerBoardStates[x][y-1] = gui.Hit
+					if a.contains(shot, a.PlayerBoard) && guiB.PlayerBoardStates[c.X][c.Y] != gui.Hit {
+						guiB.PlayerBoardStates[c.X][c.Y] = gui.Hit
 						oppHitShots = append(oppHitShots, shot)
 						res = "hit"
 					} else if !a.contains(shot, a.PlayerBoard) {
-						guiB.PlayerBoardStates[x][y-1] = gui.Miss
+						guiB.PlayerBoardStates[c.X][c.Y] = gui.Miss
 						res = "miss"
 					}
 					guiB.OppShotResult.SetText(fmt.Sprintf("%s, %s on %s", a.TargetNick, res, shot))
 				}
-				guiB.OpponentAccuracy.SetText(fmt.Sprintf("Accuracy: %v / %v", len(oppHitShots), len(a.Status.OppShots)))
+				guiB.OpponentAccuracy.SetText(fmt.Sprintf("Accuracy: %v / %v", len(oppHitShots), len(status.OppShots)))
 				guiB.PlayerBoard.SetStates(guiB.PlayerBoardStates)
 				guiB.ShouldFire.SetText("Fire!")
 				guiB.ShouldFire.SetFgColor(gui.Green)
-				char := guiB.OpponentBoard.Listen(ctx)
-				select {
-				case <-ctx.Done():
-					quitChan <- true
-				default:
-					break
-				}
-				x, y, err := a.stringCoordToInt(char)
-				if err != nil {
-					log.Fatalf("app startBattle() 13, a.stringCoordToInt(); %v", err)
-				}
-				for guiB.OpponentBoardStates[x][y-1] == gui.Miss || guiB.OpponentBoardStates[x][y-1] == gui.Hit {
-					guiB.ShouldFire.SetText("You can't fire there!")
-					char = guiB.OpponentBoard.Listen(ctx)
-					x, y, err = a.stringCoordToInt(char)
-					if err != nil {
-						log.Fatalf("app startBattle() 14, a.stringCoordToInt(); %v", err)
+				char := a.aim(ctx, guiB)
+				if char == "" {
+					// the timer loop ended the game or we are leaving it
+					if guiB.finished() {
+						return
 					}
+					continue
 				}
-				if a.Status.GameStatus == "ended" {
-					break
+				target, err := game.ParseCoord(char)
+				if err != nil {
+					log.Fatalf("app startBattle() 13, game.ParseCoord(); %v", err)
 				}
-				shots = append(shots, char)
-				result, err := a.Client.Shoot(char)
+				result, err := a.Client.Shoot(ctx, char)
 				if err != nil {
+					if ctx.Err() != nil {
+						continue
+					}
+					switch a.battleError(guiB, err) {
+					case errRetry:
+						continue
+					case errEnded:
+						a.finishBattle(guiB, err)
+						return
+					}
 					log.Fatalf("app startBattle() 15, client.Shoot(); %v", err)
 				}
 				if result == hitRes {
 					hitShots = append(hitShots, char)
-					guiB.OpponentBoardStates[x][y-1] = gui.Hit
+					guiB.OpponentBoardStates[target.X][target.Y] = gui.Hit
 				} else if result == sunkRes {
 					hitShots = append(hitShots, char)
-					guiB.OpponentBoardStates[x][y-1] = gui.Hit
-					for _, coord := range a.getAdjacentCoordinates(char) {
-						x, y, err := a.stringCoordToInt(coord)
-						if err != nil {
-							log.Fatalf("app startBattle() 16 miss check, a.stringCoordToInt(); %v", err)
-						}
-						if !a.contains(coord, hitShots) {
-							guiB.OpponentBoardStates[x][y-1] = gui.Miss
-						} else if guiB.OpponentBoardStates[x][y-1] == gui.Hit {
-							for _, sCoord := range a.getAdjacentCoordinates(coord) {
-								x, y, err := a.stringCoordToInt(sCoord)
-								if err != nil {
-									log.Fatalf("app startBattle() 17 miss check, a.stringCoordToInt(); %v", err)
-								}
-								if !a.contains(sCoord, hitShots) {
-									guiB.OpponentBoardStates[x][y-1] = gui.Miss
-								} else if guiB.OpponentBoardStates[x][y-1] == gui.Hit {
-									for _, tCoord := range a.getAdjacentCoordinates(sCoord) {
-										x, y, err := a.stringCoordToInt(tCoord)
-										if err != nil {
-											log.Fatalf("app startBattle() 18 miss check, a.stringCoordToInt(); %v", err)
-										}
-										if !a.contains(tCoord, hitShots) {
-											guiB.OpponentBoardStates[x][y-1] = gui.Miss
-										} else if guiB.OpponentBoardStates[x][y-1] == gui.Hit {
-											for _, fCoord := range a.getAdjacentCoordinates(tCoord) {
-												x, y, err := a.stringCoordToInt(fCoord)
-												if err != nil {
-													log.Fatalf("app startBattle() 19 miss check, a.stringCoordToInt(); %v", err)
-												}
-												if !a.contains(fCoord, hitShots) {
-													guiB.OpponentBoardStates[x][y-1] = gui.Miss
-												}
-											}
-										}
-									}
-								}
-							}
-						}
-					}
+					guiB.OpponentBoardStates[target.X][target.Y] = gui.Hit
+					a.markSunk(guiB, target)
 				} else if result == blankRes {
 					continue
 				} else if result == missRes {
-					guiB.OpponentBoardStates[x][y-1] = gui.Miss
+					guiB.OpponentBoardStates[target.X][target.Y] = gui.Miss
 					guiB.ShouldFire.SetText("It's not your turn!")
 					guiB.ShouldFire.SetFgColor(gui.Red)
 				}
+				shots = append(shots, char)
+				a.recordShot(char, result, status.OppShots)
 				guiB.OpponentBoard.SetStates(guiB.OpponentBoardStates)
+				guiB.showTarget()
 				guiB.ShotResult.SetText(fmt.Sprintf("%s, %s on %s", a.Nick, result, char))
 				guiB.PlayerAccuracy.SetText(fmt.Sprintf("Accuracy: %v / %v", len(hitShots), len(shots)))
-				a.Status, err = a.Client.GetStatus()
+				status, err = a.pollStatus(ctx)
 				if err != nil {
+					if ctx.Err() != nil {
+						continue
+					}
+					switch a.battleError(guiB, err) {
+					case errRetry:
+						continue
+					case errEnded:
+						a.finishBattle(guiB, err)
+						return
+					}
 					log.Fatalf("app startBattle() 20, client.GetStatus(); %v", err)
 				}
 			} else {
 				time.Sleep(waitingTime)
-				a.Status, err = a.Client.GetStatus()
+				status, err = a.pollStatus(ctx)
 				if err != nil {
+					if ctx.Err() != nil {
+						continue
+					}
+					switch a.battleError(guiB, err) {
+					case errRetry:
+						continue
+					case errEnded:
+						a.finishBattle(guiB, err)
+						return
+					}
 					log.Fatalf("app startBattle() 21, client.GetStatus(); %v", err)
 				}
 			}
-			a.Status, err = a.Client.GetStatus()
+			status, err = a.pollStatus(ctx)
 			if err != nil {
+				if ctx.Err() != nil {
+					continue
+				}
+				switch a.battleError(guiB, err) {
+				case errRetry:
+					continue
+				case errEnded:
+					a.finishBattle(guiB, err)
+					return
+				}
 				log.Fatalf("app startBattle() 22, client.GetStatus(); %v", err)
 			}
 		case <-ctx.Done():
-			err := a.Client.Abandon()
+			a.finishRecording(replay.StatusAbandoned)
+			err := a.abandon()
 			if err != nil {
 				log.Fatalf("Error abandoning, %v", err)
 			}
 			return
 		}
 	}
+	a.finishBattle(guiB, nil)
+}
+
+// finishBattle shows how the game ended and files it. The battle and timer
+// loops both call it when they notice the end, only the first call counts.
+// err is the request error when the server dropped the game.
+func (a *App) finishBattle(guiB *GuiBattle, err error) {
+	guiB.endOnce.Do(func() {
+		if err != nil {
+			guiB.ShouldFire.SetText("Game is no longer available")
+			guiB.ShouldFire.SetFgColor(gui.Red)
+			guiB.Exit.SetText("To start a new game press CTRL+C")
+			guiB.Ui.Log(fmt.Sprintf("Game ended by server: %v", err))
+		} else {
+			a.showResult(guiB)
+		}
+		a.endGame()
+		close(guiB.over)
+	})
+}
+
+// showResult replaces the boards with the result of the game.
+func (a *App) showResult(guiB *GuiBattle) {
+	status := a.status()
 	winner := a.Nick
 	cfg := gui.TextConfig{BgColor: gui.Green, FgColor: gui.White}
-	if a.Status.LastGameStatus == "lose" {
+	if status.LastGameStatus == "lose" {
 		winner = a.TargetNick
 		cfg = gui.TextConfig{BgColor: gui.Red}
 	}
@@ -357,67 +424,35 @@ func (a *App) startBattle(guiB *GuiBattle, ctx context.Context, cancelCtx contex
 	guiB.Ui.Remove(guiB.ShouldFire)
 	guiB.Ui.Remove(guiB.Timer)
 	guiB.Ui.Remove(guiB.OpponentAccuracy)
-	guiB.Ui.Draw(gui.NewText(xPBoard, yBoards, fmt.Sprintf("You %s!", a.Status.LastGameStatus), &cfg))
+	guiB.Ui.Remove(guiB.Autopilot)
+	guiB.Ui.Remove(guiB.Cursor)
+	guiB.Ui.Remove(guiB.Target)
+	guiB.Ui.Draw(gui.NewText(xPBoard, yBoards, fmt.Sprintf("You %s!", status.LastGameStatus), &cfg))
 	guiB.Ui.Draw(gui.NewText(xPBoard, yBoards+3, fmt.Sprintf("Winner: %s", winner), &cfg))
 	guiB.Exit.SetText("To start a new game press CTRL+C")
 	guiB.Ui.Log(fmt.Sprintf("Winner: %s", winner))
-	quitChan <- true
 }
 
-var ErrInvalidCoord = errors.New("invalid coordinate")
-
-// stringCoordToInt converts a string coordinate to int coordinates
-func (a *App) stringCoordToInt(coord string) (int, int, error) {
-	if len(coord) < 2 || len(coord) > 3 {
-		return 0, 0, ErrInvalidCoord
-	}
-	coord = strings.ToUpper(coord)
-	if coord[0] < 'A' || coord[0] > 'K' {
-		return 0, 0, ErrInvalidCoord
-	}
-	x := int(coord[0] - 'A')
-	y, err := strconv.Atoi(coord[1:])
-	if err != nil {
-		return 0, 0, ErrInvalidCoord
+// finished reports whether finishBattle already ended the game.
+func (guiB *GuiBattle) finished() bool {
+	select {
+	case <-guiB.over:
+		return true
+	default:
+		return false
 	}
-	return x, y, nil
 }
 
-func (a *App) intCoordToString(x, y int) (string, error) {
-	if x < 0 || x >= 10 || y < 1 || y > 10 {
-		return "", ErrInvalidCoord
+// endGame files the replay and history entry of the game and forgets its
+// session, also when the server dropped the game before it was decided.
+func (a *App) endGame() {
+	s := a.status()
+	status := s.LastGameStatus
+	if s.GameStatus != "ended" || status == "" {
+		status = replay.StatusAbandoned
 	}
-	return string(rune('A'+x)) + strconv.Itoa(y), nil
-}
-
-func (a *App) mappingInts(coords [][]int) ([]string, error) {
-	var resSlice []string
-	for _, coord := range coords {
-		if len(coord) != 2 {
-			return nil, ErrInvalidCoord
-		}
-		x, y := coord[0], coord[1]
-		strCoord, err := a.intCoordToString(x, y)
-		if err != nil {
-			return nil, err
-		}
-		resSlice = append(resSlice, strCoord)
-	}
-	return resSlice, nil
-}
-
-// mappingChars maps layout from a slice of strings to a format which can be read by gui
-func (a *App) mappingChars(layout []string) ([][]int, error) {
-	var resSlice [][]int
-	for _, i := range layout {
-		x, y, err := a.stringCoordToInt(i)
-		if err != nil {
-			log.Fatalf("app mappingChars() 25, a.stringCoordToInt(); %v", err)
-			return nil, err
-		}
-		resSlice = append(resSlice, []int{x, y})
-	}
-	return resSlice, nil
+	a.finishRecording(status)
+	a.clearSession()
 }
 
 func (a *App) contains(e string, s []string) bool {
@@ -620,18 +655,17 @@ func (a *App) validateDescription(description string) bool {
 	return len(description) >= 5 && len(description) <= 200
 }
 
-func (a *App) getDetails(c *client.Client, ctx context.Context, cancelFunc context.CancelFunc) (client.Game, error) {
+func (a *App) getDetails(c client.GameAPI, ctx, ctxFleet context.Context, cancelFunc context.CancelFunc) (client.Game, error) {
 	var nick string
 	var pDes string
-	game := client.Game{}
 	if err := termui.Init(); err != nil {
 		log.Fatalf("Failed to initialize termui 32: %v", err)
 	}
 
-	options := []string{"Play with a bot", "Wait for an opponent", "Challenge someone", "Show stats"}
+	options := []string{"Play with a bot", "Play offline vs bot", "Wait for an opponent", "Challenge someone", "Show stats", "My stats", "Toggle autopilot"}
 
 	list := widgets.NewList()
-	list.Title = "Choose an Option"
+	list.Title = a.menuTitle()
 	list.Rows = options
 	list.SelectedRowStyle = termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
 
@@ -659,46 +693,85 @@ mainLoop:
 					if nick != "" {
 						termui.Clear()
 						pDes = a.getPlayerDescription()
-						fleet := a.getLayout(a.Ui, ctx, cancelFunc)
+						fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
 						termui.Clear()
 						if len(fleet) != 0 {
-							game, err := c.InitGame(client.Game{Nick: nick, Desc: pDes, WPBot: true, Coords: fleet})
+							game, err := a.initGame(ctx, c, client.Game{Nick: nick, Desc: pDes, WPBot: true, Coords: fleet})
 							if err != nil {
 								log.Fatalf("a.getDetails 33, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						} else {
-							game, err := c.InitGame(client.Game{Nick: nick, Desc: pDes, WPBot: true})
+							a.askServerLayoutName(uiEvents)
+							game, err := a.initGame(ctx, c, client.Game{Nick: nick, Desc: pDes, WPBot: true})
 							if err != nil {
 								log.Fatalf("a.getDetails 33, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						}
 					} else {
-						fleet := a.getLayout(a.Ui, ctx, cancelFunc)
+						fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
 						termui.Clear()
 						if len(fleet) != 0 {
-							game, err := c.InitGame(client.Game{WPBot: true, Coords: fleet})
+							game, err := a.initGame(ctx, c, client.Game{WPBot: true, Coords: fleet})
 							if err != nil {
 								log.Fatalf("a.getDetails 34, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						} else {
-							game, err := c.InitGame(client.Game{WPBot: true})
+							a.askServerLayoutName(uiEvents)
+							game, err := a.initGame(ctx, c, client.Game{WPBot: true})
 							if err != nil {
 								log.Fatalf("a.getDetails 34, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						}
 					}
+				} else if selectedOption == "Play offline vs bot" {
+					difficulty, ok := a.pickDifficulty(uiEvents)
+					if !ok {
+						termui.Render(list)
+						continue mainLoop
+					}
+					nick = a.getPlayerName()
+					termui.Clear()
+					if nick != "" {
+						pDes = a.getPlayerDescription()
+						termui.Clear()
+					}
+					fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
+					termui.Clear()
+					a.Client = bot.NewLocalGame(bot.LocalOptions{Difficulty: difficulty})
+					game, err := a.initGame(ctx, a.Client, client.Game{Nick: nick, Desc: pDes, Coords: fleet})
+					if err != nil {
+						log.Fatalf("a.getDetails 42, c.InitGame; %v", err)
+					}
+					termui.Close()
+					return game, nil
 				} else if selectedOption == "Wait for an opponent" {
 					termui.Clear()
 					nick = a.getPlayerName()
@@ -708,43 +781,61 @@ mainLoop:
 						pDes = a.getPlayerDescription()
 						termui.Clear()
 
-						fleet := a.getLayout(a.Ui, ctx, cancelFunc)
+						fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
 						termui.Clear()
 
 						if len(fleet) != 0 {
-							game, err := c.InitGame(client.Game{Nick: nick, Desc: pDes, WPBot: false, Coords: fleet})
+							game, err := a.initGame(ctx, c, client.Game{Nick: nick, Desc: pDes, WPBot: false, Coords: fleet})
 							if err != nil {
 								log.Fatalf("a.getDetails 35, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						} else {
-							game, err := c.InitGame(client.Game{Nick: nick, Desc: pDes, WPBot: false})
+							a.askServerLayoutName(uiEvents)
+							game, err := a.initGame(ctx, c, client.Game{Nick: nick, Desc: pDes, WPBot: false})
 							if err != nil {
 								log.Fatalf("a.getDetails 35, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						}
 					} else {
-						fleet := a.getLayout(a.Ui, ctx, cancelFunc)
+						fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
 						termui.Clear()
 						if len(fleet) != 0 {
-							game, err := c.InitGame(client.Game{WPBot: false, Coords: fleet})
+							game, err := a.initGame(ctx, c, client.Game{WPBot: false, Coords: fleet})
 							if err != nil {
 								log.Fatalf("a.getDetails 36, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						} else {
-							game, err := c.InitGame(client.Game{WPBot: false})
+							a.askServerLayoutName(uiEvents)
+							game, err := a.initGame(ctx, c, client.Game{WPBot: false})
 							if err != nil {
 								log.Fatalf("a.getDetails 36, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						}
@@ -756,132 +847,91 @@ mainLoop:
 						termui.Clear()
 						pDes = a.getPlayerDescription()
 
-						fleet := a.getLayout(a.Ui, ctx, cancelFunc)
+						fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
 						termui.Clear()
 
-						targetNick := a.getTarget(c)
+						targetNick := a.getTarget(ctx, c, uiEvents)
 						if targetNick == "" {
-							log.Fatalf("Target nick cannot be empty")
+							termui.Render(list)
+							continue mainLoop
 						}
 						if len(fleet) != 0 {
-							game, err := c.InitGame(client.Game{Nick: nick, Desc: pDes, WPBot: false, TargetNick: targetNick, Coords: fleet})
+							game, err := a.initGame(ctx, c, client.Game{Nick: nick, Desc: pDes, WPBot: false, TargetNick: targetNick, Coords: fleet})
 							if err != nil {
 								log.Fatalf("a.getDetails 37, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						} else {
-							game, err := c.InitGame(client.Game{Nick: nick, Desc: pDes, WPBot: false, TargetNick: targetNick})
+							a.askServerLayoutName(uiEvents)
+							game, err := a.initGame(ctx, c, client.Game{Nick: nick, Desc: pDes, WPBot: false, TargetNick: targetNick})
 							if err != nil {
 								log.Fatalf("a.getDetails 37, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						}
 					} else {
 						termui.Clear()
-						fleet := a.getLayout(a.Ui, ctx, cancelFunc)
+						fleet := a.getLayout(a.Ui, ctxFleet, cancelFunc)
 						termui.Clear()
-						targetNick := a.getTarget(c)
+						targetNick := a.getTarget(ctx, c, uiEvents)
 						if targetNick == "" {
-							log.Fatalf("Target nick cannot be empty")
+							termui.Render(list)
+							continue mainLoop
 						}
 						if len(fleet) != 0 {
-							game, err := c.InitGame(client.Game{WPBot: false, TargetNick: targetNick, Coords: fleet})
+							game, err := a.initGame(ctx, c, client.Game{WPBot: false, TargetNick: targetNick, Coords: fleet})
 							if err != nil {
 								log.Fatalf("a.getDetails 38, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						} else {
-							game, err := c.InitGame(client.Game{WPBot: false, TargetNick: targetNick})
+							a.askServerLayoutName(uiEvents)
+							game, err := a.initGame(ctx, c, client.Game{WPBot: false, TargetNick: targetNick})
 							if err != nil {
 								log.Fatalf("a.getDetails 38, c.InitGame; %v", err)
 							}
-							a.waitForOpponent(waitingTime)
+							game, ok := a.waitRoom(ctx, c, game, uiEvents)
+							if !ok {
+								termui.Render(list)
+								continue mainLoop
+							}
 							termui.Close()
 							return game, nil
 						}
 					}
-				} else if selectedOption == "Show stats" {
-					termui.Clear()
-					stats, err := a.Client.GetStats()
-					if err != nil {
-						log.Fatal(err)
-					}
-					stringStats := make([]string, 0)
-					for i, stat := range stats.Stats {
-						str := fmt.Sprintf("%d. Games: %d, Nick: %s, Points: %d, Rank: %d, Wins: %d",
-							i+1, stat.Games, stat.Nick, stat.Points, stat.Rank, stat.Wins)
-						stringStats = append(stringStats, str)
-					}
-					stringStats = append(stringStats, "Back")
-
-					statList := widgets.NewList()
-					statList.Rows = stringStats
-					statList.SelectedRowStyle = termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
-
-					a.triggerResize(statList)
-					termui.Render(statList)
-
-					// Stats loop
-					for {
-						statEv := <-uiEvents
-						switch statEv.Type {
-						case termui.KeyboardEvent:
-							switch statEv.ID {
-							case "<Down>":
-								statList.ScrollDown()
-							case "<Up>":
-								statList.ScrollUp()
-							case "<Enter>":
-								selectedStat := stringStats[statList.SelectedRow]
-								if selectedStat == "Back" {
-									termui.Clear()
-									termui.Render(list)
-									continue mainLoop
-								}
-							case "<Escape>":
-								termui.Close()
-								kb, err := keybd_event.NewKeyBonding()
-								if err != nil {
-									log.Fatalf("ui keybind 39, keybd_event.NewKeyBonding(); %v", err)
-								}
-								if runtime.GOOS == "linux" {
-									time.Sleep(2 * time.Second)
-								}
-
-								kb.SetKeys(keybd_event.VK_C)
-
-								kb.HasCTRL(true)
-
-								err = kb.Launching()
-								if err != nil {
-									log.Fatal(err)
-								}
-
-								err = kb.Press()
-								if err != nil {
-									log.Fatalf("ui keypress 40, kb.Press(); %v", err)
-								}
-								time.Sleep(10 * time.Millisecond)
-								err = kb.Release()
-								if err != nil {
-									log.Fatalf("ui keypress 41, kb.Release(); %v", err)
-								}
-								break
-							}
-							termui.Render(statList)
-						case termui.ResizeEvent:
-							payload := ev.Payload.(termui.Resize)
-							termui.Clear()
-							list.SetRect(0, 0, payload.Width, payload.Height)
+				} else if selectedOption == "My stats" {
+					a.showMyStats(uiEvents)
+				} else if selectedOption == "Toggle autopilot" {
+					if !a.Autopilot {
+						difficulty, ok := a.pickDifficulty(uiEvents)
+						if !ok {
 							termui.Render(list)
+							continue mainLoop
 						}
+						a.AutopilotLevel = difficulty
 					}
+					a.Autopilot = !a.Autopilot
+					list.Title = a.menuTitle()
+				} else if selectedOption == "Show stats" {
+					a.showLeaderboard(ctx, uiEvents)
 				}
 			case "<Escape>":
 				termui.Close()
@@ -922,11 +972,10 @@ mainLoop:
 		}
 
 	}
-	return game, nil
 }
 
 func (a *App) getLayout(ui *gui.GUI, ctx context.Context, cancelFunc context.CancelFunc) []string {
-	options := []string{"Yes", "No"}
+	options := []string{"Yes", "Yes, ship by ship", "No", "Randomize", "Saved layout"}
 	termui.Clear()
 	list := widgets.NewList()
 	list.Title = "Do you want to build your own fleet?"
@@ -952,9 +1001,29 @@ func (a *App) getLayout(ui *gui.GUI, ctx context.Context, cancelFunc context.Can
 					if err != nil {
 						log.Fatalf("app Start() 4, a.makeFleet(); %v", err)
 					}
+					if fleet != nil {
+						a.offerSaveLayout(uiEvents, saveLayoutTitle(fleet), fleet)
+					}
 					return fleet
+				} else if selectedOption == "Yes, ship by ship" {
+					if fleet := a.placeShips(uiEvents); fleet != nil {
+						a.offerSaveLayout(uiEvents, saveLayoutTitle(fleet), fleet)
+						return fleet
+					}
+					termui.Render(list)
 				} else if selectedOption == "No" {
 					return nil
+				} else if selectedOption == "Randomize" {
+					if fleet := a.randomFleet(uiEvents); fleet != nil {
+						a.offerSaveLayout(uiEvents, saveLayoutTitle(fleet), fleet)
+						return fleet
+					}
+					termui.Render(list)
+				} else if selectedOption == "Saved layout" {
+					if fleet := a.pickLayout(uiEvents); fleet != nil {
+						return fleet
+					}
+					termui.Render(list)
 				}
 			case "<Escape>":
 				termui.Close()
@@ -997,201 +1066,13 @@ func (a *App) getLayout(ui *gui.GUI, ctx context.Context, cancelFunc context.Can
 
 }
 
-func (a *App) getTarget(c *client.Client) string {
-	err := termui.Init()
-	if err != nil {
-		return ""
-	}
-	playerList, err := c.GetPlayers()
-	if err != nil {
-		log.Fatalf("a.getTarget 42, c.GetPlayers; %v", err)
-	}
-
-	arePlayers := false
-	if len(playerList) == 0 {
-		time.Sleep(waitingTime * 3)
-		playerList, err := c.GetPlayers()
-		if err != nil {
-			log.Fatalf("a.getTarget 43, c.GetPlayers; %v", err)
-		}
-		if len(playerList) == 0 {
-			timerMsg := widgets.NewParagraph()
-			timerMsg.Text = "No players available"
-			timerMsg.TextStyle = termui.NewStyle(termui.ColorYellow)
-			timerMsg.SetRect(0, 0, 50, 3)
-
-			termui.Render(timerMsg)
-
-			countdown := countdownTime
-			ticker := time.NewTicker(time.Second * 1)
-			defer ticker.Stop()
-
-			for range ticker.C {
-				countdown--
-				timerMsg.Text = fmt.Sprintf("No players available - Waiting %d seconds", countdown)
-				termui.Render(timerMsg)
-				if countdown%2 == 0 {
-					playerList, err = c.GetPlayers()
-					if err != nil {
-						log.Fatalf("a.getTarget 44, c.GetPlayers; %v", err)
-					}
-				}
-				if countdown == 0 || len(playerList) != 0 {
-					arePlayers = true
-					break
-				}
-			}
-
-			termui.Clear()
-
-			if !arePlayers {
-				noPlayersMsg := widgets.NewParagraph()
-				noPlayersMsg.Text = "No players available, Connection lost"
-				noPlayersMsg.TextStyle = termui.NewStyle(termui.ColorYellow)
-				noPlayersMsg.SetRect(0, 0, 30, 3)
-
-				termui.Render(noPlayersMsg)
-				uiEvents := termui.PollEvents()
-				for {
-					ev := <-uiEvents
-					if ev.Type == termui.KeyboardEvent && ev.ID == "<Enter>" || ev.Type == termui.KeyboardEvent && ev.ID == "<Escape>" {
-						termui.Clear()
-						return ""
-					}
-				}
-			}
-		}
-
-	} else {
-		arePlayers = true
-	}
-
-	playerList, err = c.GetPlayers()
-	if err != nil {
-		log.Fatalf("a.getTarget 45, c.GetPlayers; %v", err)
-	}
-	selectedPlayerIndex := 0
-	selectedPlayerStyle := termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
-
-	playerNames := make([]string, len(playerList))
-	for i, player := range playerList {
-		playerNames[i] = player.Nick
-	}
-
-	playerListWidget := widgets.NewList()
-	playerListWidget.Title = "Select a Player"
-	playerListWidget.Rows = playerNames
-	playerListWidget.SelectedRow = selectedPlayerIndex
-	playerListWidget.SelectedRowStyle = selectedPlayerStyle
-
-	termui.Render(playerListWidget)
-	a.triggerResize(playerListWidget)
-
-	uiEvents := termui.PollEvents()
-	for {
-		ev := <-uiEvents
-		switch ev.Type {
-		case termui.KeyboardEvent:
-			switch ev.ID {
-			case "<Down>":
-				if selectedPlayerIndex < len(playerList)-1 {
-					selectedPlayerIndex++
-					playerListWidget.SelectedRow = selectedPlayerIndex
-				}
-			case "<Up>":
-				if selectedPlayerIndex > 0 {
-					selectedPlayerIndex--
-					playerListWidget.SelectedRow = selectedPlayerIndex
-				}
-			case "<Enter>":
-				selectedPlayer := playerList[selectedPlayerIndex].Nick
-				termui.Clear()
-				return selectedPlayer
-			case "<Escape>":
-				termui.Close()
-				kb, err := keybd_event.NewKeyBonding()
-				if err != nil {
-					log.Fatalf("ui keybind 46, keybd_event.NewKeyBonding(); %v", err)
-				}
-				if runtime.GOOS == "linux" {
-					time.Sleep(2 * time.Second)
-				}
-
-				kb.SetKeys(keybd_event.VK_C)
-
-				kb.HasCTRL(true)
-
-				err = kb.Launching()
-				if err != nil {
-					log.Fatal(err)
-				}
-
-				err = kb.Press()
-				if err != nil {
-					log.Fatalf("ui keypress 47, kb.Press(); %v", err)
-				}
-				time.Sleep(10 * time.Millisecond)
-				err = kb.Release()
-				if err != nil {
-					log.Fatalf("ui keypress 48, kb.Release(); %v", err)
-				}
-				break
-			}
-			termui.Render(playerListWidget)
-		case termui.ResizeEvent:
-			payload := ev.Payload.(termui.Resize)
-			termui.Clear()
-			playerListWidget.SetRect(0, 0, payload.Width, payload.Height)
-			termui.Render(playerListWidget)
-		}
-	}
-}
-func (a *App) waitForOpponent(waitingTime time.Duration) {
-	err := termui.Init()
-	if err != nil {
-		return
-	}
-	termui.Clear()
-	a.Status, err = a.Client.GetStatus()
-	if err != nil {
-		log.Fatalf("a.waitForOpponent 49, c.GetStatus; %v", err)
-	}
-	waitingMsg := widgets.NewParagraph()
-	waitingMsg.TextStyle = termui.NewStyle(termui.ColorYellow)
-	waitingMsg.SetRect(0, 0, 30, 3)
-
-	termui.Render(waitingMsg)
-
-	for a.Status.GameStatus == "waiting" || a.Status.GameStatus == "waiting_wpbot" {
-		var err error
-		time.Sleep(waitingTime)
-		a.Status, err = a.Client.GetStatus()
-		if err != nil {
-			log.Fatalf("a.waitForOpponent 50, c.GetStatus; %v", err)
-		}
-
-		switch time.Now().Second() % 3 {
-		case 0:
-			waitingMsg.Text = "Waiting for an opponent."
-		case 1:
-			waitingMsg.Text = "Waiting for an opponent.."
-		case 2:
-			waitingMsg.Text = "Waiting for an opponent..."
-		}
-
-		termui.Render(waitingMsg)
-	}
-
-	termui.Clear()
-}
-
 func (a *App) makeUI() (*gui.GUI, error) {
 	ui := gui.NewGUI(true)
 	return ui, nil
 }
 
 func (a *App) buildBattlefield(ui *gui.GUI) *GuiBattle {
-	guiBattle := GuiBattle{}
+	guiBattle := GuiBattle{over: make(chan struct{})}
 	guiBattle.Ui = ui
 
 	accConfig := gui.TextConfig{BgColor: gui.Grey, FgColor: gui.Blue}
@@ -1220,6 +1101,14 @@ func (a *App) buildBattlefield(ui *gui.GUI) *GuiBattle {
 	guiBattle.Ui.Draw(shotRes)
 	guiBattle.ShotResult = shotRes
 
+	autopilot := gui.NewText(xOBoard, yBoards-5, "", nil)
+	guiBattle.Ui.Draw(autopilot)
+	guiBattle.Autopilot = autopilot
+
+	keys := newKeyListener()
+	guiBattle.Ui.Draw(keys)
+	guiBattle.Keys = keys
+
 	oppShotRes := gui.NewText(xOBoard, yBoards-2, "", nil)
 	guiBattle.Ui.Draw(oppShotRes)
 	guiBattle.OppShotResult = oppShotRes
@@ -1241,6 +1130,14 @@ func (a *App) buildBattlefield(ui *gui.GUI) *GuiBattle {
 		oStates[i] = [10]gui.State{}
 	}
 	oBoard.SetStates(oStates)
+	cur := newCursor(xOBoard, yBoards)
+	guiBattle.Ui.Draw(cur)
+	guiBattle.Cursor = cur
+	target := gui.NewText(xOBoard, yBoards-4, "", &gui.TextConfig{BgColor: gui.Black, FgColor: gui.White})
+	guiBattle.Ui.Draw(target)
+	guiBattle.Target = target
+	guiBattle.showTarget()
+	a.showAutopilot(&guiBattle)
 
 	nickConfig := gui.TextConfig{BgColor: gui.Black, FgColor: gui.White}
 
@@ -1283,11 +1180,15 @@ func (a *App) formatString(s string, n, x, y int, ui *gui.GUI) {
 	}
 }
 
-func (a *App) makeFleet(ui *gui.GUI, ctx context.Context, ctxCancel context.CancelFunc) ([]string, error) {
+// makeFleet lets the player click a valid 20 cell fleet, highlighting rule
+// violations live. It runs on a child of ctx so it can be reopened later.
+func (a *App) makeFleet(ui *gui.GUI, ctx context.Context, _ context.CancelFunc) ([]string, error) {
+	ctx, ctxCancel := context.WithCancel(ctx)
+	defer ctxCancel()
 	cfg := gui.NewBoardConfig()
-	cfg.HitChar = 'A'
-	cfg.HitColor = gui.Grey
-	board := gui.NewBoard(35, 1, cfg)
+	cfg.HitChar = '!'
+	cfg.HitColor = gui.Red
+	board := gui.NewBoard(xFleetBoard, yFleetBoard, cfg)
 	states := [10][10]gui.State{}
 	for i := range states {
 		for j := range states[i] {
@@ -1295,101 +1196,87 @@ func (a *App) makeFleet(ui *gui.GUI, ctx context.Context, ctxCancel context.Canc
 		}
 	}
 	board.SetStates(states)
-	shipCoords := make([]string, 0)
 	ui.Draw(board)
+	hints := make([]*gui.Text, fleetHintLines)
+	for i := range hints {
+		hints[i] = gui.NewText(xFleetBoard, yFleetHints+i, "", nil)
+		ui.Draw(hints[i])
+	}
+	hints[0].SetText(fmt.Sprintf("Selected 0/%d cells", game.FleetCells))
 
+	done := make(chan []string, 1)
 	go func() {
-		for len(shipCoords) != 20 {
+		shipCoords := make([]string, 0)
+		for {
 			char := board.Listen(ctx)
-			x, y, err := a.stringCoordToInt(char)
-			if err != nil {
-				log.Fatal(err)
+			if char == "" {
+				return
 			}
-			if states[x][y-1] != gui.Ship {
-				states[x][y-1] = gui.Ship
-				shipCoords = append(shipCoords, char)
-			} else {
-				states[x][y-1] = gui.Empty
+			if a.contains(char, shipCoords) {
 				for j := len(shipCoords) - 1; j >= 0; j-- {
 					if shipCoords[j] == char {
 						shipCoords = append(shipCoords[:j], shipCoords[j+1:]...)
 						break
 					}
 				}
+			} else {
+				shipCoords = append(shipCoords, char)
 			}
-			board.SetStates(states)
-		}
-		ui.Remove(board)
-		ctxCancel()
-	}()
-
-	ui.Start(ctx, nil)
-
-	return shipCoords, nil
-}
-func (a *App) checkShips(coords []string) bool {
-	shipCount := map[int]int{
-		1: 4, // One-tilers
-		2: 3, // Two-tilers
-		3: 2, // Three-tilers
-		4: 1, // Four-tilers
-	}
-
-	coordMap := make(map[string]bool)
-	for _, coord := range coords {
-		coordMap[coord] = true
-	}
-
-	for size, count := range shipCount {
-		missingCount := count
-		for coord := range coordMap {
-			x, y, err := a.stringCoordToInt(coord)
+			coords, err := game.ParseCoords(shipCoords)
 			if err != nil {
-				log.Fatalf("a.checkShips: stringCoordToInt; %v", err)
+				log.Fatal(err)
 			}
-			if a.isValidShip(coordMap, size, x, y) {
-				missingCount--
-				if missingCount == 0 {
-					break
+			violations := a.placementViolations(coords)
+			if len(coords) == game.FleetCells && len(violations) == 0 {
+				ui.Remove(board)
+				for _, h := range hints {
+					ui.Remove(h)
 				}
+				done <- shipCoords
+				ctxCancel()
+				return
 			}
-		}
-		if missingCount > 0 {
-			return false
-		}
-	}
-
-	return true
-}
-
-func (a *App) isValidShip(coordMap map[string]bool, size, x, y int) bool {
-	for dx := -1; dx <= 1; dx++ {
-		for dy := -1; dy <= 1; dy++ {
-			if dx == 0 && dy == 0 {
-				continue
+			states = toStates(game.NewBoard(coords))
+			for _, c := range violations.Cells() {
+				states[c.X][c.Y] = gui.Hit
 			}
-			if a.isShipInOrientation(coordMap, size, x, y, dx, dy) {
-				return true
+			board.SetStates(states)
+			msgs := []string{fmt.Sprintf("Selected %d/%d cells", len(coords), game.FleetCells)}
+			for _, v := range violations {
+				msgs = append(msgs, v.Message)
+			}
+			for i, h := range hints {
+				if i < len(msgs) {
+					h.SetText(msgs[i])
+				} else {
+					h.SetText("")
+				}
 			}
 		}
+	}()
+
+	ui.Start(ctx, nil)
+
+	select {
+	case fleet := <-done:
+		return fleet, nil
+	default:
+		return nil, nil
 	}
-	return false
 }
 
-func (a *App) isShipInOrientation(coordMap map[string]bool, size, x, y, dx, dy int) bool {
-	for i := 0; i < size; i++ {
-		newX := x + (i * dx)
-		newY := y + (i * dy)
-		if newX < 0 || newX >= 10 || newY < 1 || newY > 10 {
-			continue
-		}
-		coord, err := a.intCoordToString(newX, newY)
-		if err != nil {
-			log.Fatalf("a.isShipInOrientation: intCoordToString; %v", err)
-		}
-		if _, ok := coordMap[coord]; !ok {
-			return false
+// placementViolations validates a fleet under construction. Counting rules
+// only apply once all cells are placed, shape and contact rules apply live.
+func (a *App) placementViolations(coords []game.Coord) game.Violations {
+	violations := game.ValidateLayout(coords)
+	if len(coords) >= game.FleetCells {
+		return violations
+	}
+	var live game.Violations
+	for _, v := range violations {
+		if v.Kind != game.ViolationCells && v.Kind != game.ViolationShips {
+			live = append(live, v)
 		}
 	}
-	return true
+	return live
 }
@@ -0,0 +1,152 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"main/bot"
+	"main/client"
+	"main/replay"
+)
+
+// battleGame is a FakeClient whose status follows the shots fired so far,
+// so the battle and timer loops can poll it in any order.
+type battleGame struct {
+	*client.FakeClient
+	status func(shots int) client.StatusResponse
+}
+
+func (g *battleGame) GetStatus(ctx context.Context) (client.StatusResponse, error) {
+	if _, err := g.FakeClient.GetStatus(ctx); err != nil {
+		return client.StatusResponse{}, err
+	}
+	return g.status(len(g.ShotsFired())), nil
+}
+
+func playing(shouldFire bool) client.StatusResponse {
+	return client.StatusResponse{GameStatus: "game_in_progress", ShouldFire: shouldFire, Timer: 60, Opponent: "bob"}
+}
+
+func ended(result string, oppShots ...string) client.StatusResponse {
+	return client.StatusResponse{GameStatus: "ended", LastGameStatus: result, Opponent: "bob", OppShots: oppShots}
+}
+
+func TestStartBattle(t *testing.T) {
+	tests := []struct {
+		name      string
+		autopilot bool
+		results   []string
+		status    func(start time.Time, shots int) client.StatusResponse
+		err       error
+		filed     string
+		shots     int
+	}{
+		{
+			name:      "win",
+			autopilot: true,
+			results:   []string{hitRes, sunkRes},
+			status: func(_ time.Time, shots int) client.StatusResponse {
+				if shots < 2 {
+					return playing(true)
+				}
+				return ended(replay.StatusWin)
+			},
+			filed: replay.StatusWin,
+			shots: 2,
+		},
+		{
+			name:      "loss",
+			autopilot: true,
+			status: func(_ time.Time, shots int) client.StatusResponse {
+				if shots < 1 {
+					return playing(true)
+				}
+				return ended(replay.StatusLose, "A1", "B1", "C1", "D1")
+			},
+			filed: replay.StatusLose,
+			shots: 1,
+		},
+		{
+			// nobody clicks, the timer loop notices the lost turn
+			name: "timeout",
+			status: func(start time.Time, _ int) client.StatusResponse {
+				if time.Since(start) < time.Second {
+					return playing(true)
+				}
+				return ended(replay.StatusLose)
+			},
+			filed: replay.StatusLose,
+		},
+		{
+			name:   "status error",
+			status: func(time.Time, int) client.StatusResponse { return playing(true) },
+			err:    client.ErrGameNotFound,
+			filed:  replay.StatusAbandoned,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			fake := client.NewFakeClient()
+			fake.PushShotResults(tt.results...)
+			api := &battleGame{FakeClient: fake, status: func(shots int) client.StatusResponse {
+				return tt.status(start, shots)
+			}}
+			a, guiB := newTestBattle(t, api)
+			a.Autopilot = tt.autopilot
+			a.AutopilotLevel = bot.Easy
+			if tt.err != nil {
+				fake.Errs["GetStatus"] = tt.err
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan struct{})
+			go func() {
+				a.startBattle(guiB, ctx, cancel)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("startBattle did not return")
+			}
+
+			if !guiB.finished() {
+				t.Error("battle not finished")
+			}
+			if got := len(fake.ShotsFired()); got != tt.shots {
+				t.Errorf("%d shots fired, want %d", got, tt.shots)
+			}
+			if fake.Abandoned {
+				t.Error("game abandoned after it ended")
+			}
+			checkFiled(t, a, tt.filed, tt.shots)
+		})
+	}
+}
+
+func TestStartBattleLeave(t *testing.T) {
+	fake := client.NewFakeClient()
+	api := &battleGame{FakeClient: fake, status: func(int) client.StatusResponse { return playing(false) }}
+	a, guiB := newTestBattle(t, api)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.startBattle(guiB, ctx, cancel)
+		close(done)
+	}()
+	time.Sleep(2 * waitingTime)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("startBattle did not return")
+	}
+	if !fake.Abandoned {
+		t.Error("game not abandoned")
+	}
+	checkFiled(t, a, replay.StatusAbandoned, 0)
+}
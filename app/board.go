@@ -0,0 +1,43 @@
+package app
+
+import (
+	gui "github.com/grupawp/warships-gui/v2"
+	"main/game"
+)
+
+var cellStates = map[game.Cell]gui.State{
+	game.CellEmpty: gui.Empty,
+	game.CellShip:  gui.Ship,
+	game.CellHit:   gui.Hit,
+	game.CellMiss:  gui.Miss,
+}
+
+// toStates converts a game board to the states drawn by gui.Board.
+func toStates(b *game.Board) [10][10]gui.State {
+	states := [10][10]gui.State{}
+	for _, c := range game.AllCoords() {
+		states[c.X][c.Y] = cellStates[b.At(c)]
+	}
+	return states
+}
+
+// toBoard converts gui states back to a game board.
+func toBoard(states [10][10]gui.State) *game.Board {
+	b := &game.Board{}
+	for _, c := range game.AllCoords() {
+		for cell, state := range cellStates {
+			if states[c.X][c.Y] == state {
+				b.Set(c, cell)
+			}
+		}
+	}
+	return b
+}
+
+// markSunk marks the cells around the sunk ship containing c as misses on
+// the opponent board.
+func (a *App) markSunk(guiB *GuiBattle, c game.Coord) {
+	b := toBoard(guiB.OpponentBoardStates)
+	b.MarkSunk(c)
+	guiB.OpponentBoardStates = toStates(b)
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	tl "github.com/grupawp/termloop"
+	gui "github.com/grupawp/warships-gui/v2"
+	"main/game"
+)
+
+const (
+	// cell layout of gui.Board: 3 wide, one column and row of spacing
+	cellWidth  = 3
+	cellStepX  = cellWidth + 1
+	cellStepY  = 2
+	targetHelp = "arrows/WASD/hjkl move, type E7, Enter fires"
+)
+
+var cursorAttr = tl.RgbTo256Color(230, 200, 40)
+
+// cursor marks the cell of a gui.Board aimed at from the keyboard by
+// drawing brackets in the spacing around it.
+type cursor struct {
+	id   uuid.UUID
+	x, y int
+
+	mu     sync.Mutex
+	coord  game.Coord
+	hidden bool
+}
+
+func newCursor(x, y int) *cursor {
+	return &cursor{id: uuid.New(), x: x, y: y, coord: game.Coord{X: 4, Y: 4}}
+}
+
+func (c *cursor) ID() uuid.UUID {
+	return c.id
+}
+
+func (c *cursor) Drawables() []tl.Drawable {
+	return []tl.Drawable{c}
+}
+
+func (c *cursor) Tick(tl.Event) {}
+
+func (c *cursor) Draw(s *tl.Screen) {
+	c.mu.Lock()
+	coord, hidden := c.coord, c.hidden
+	c.mu.Unlock()
+	if hidden {
+		return
+	}
+	left := c.x + (coord.X+1)*cellStepX - 1
+	y := c.y + (coord.Y+1)*cellStepY
+	s.RenderCell(left, y, &tl.Cell{Fg: cursorAttr, Ch: '['})
+	s.RenderCell(left+cellWidth+1, y, &tl.Cell{Fg: cursorAttr, Ch: ']'})
+}
+
+func (c *cursor) at() game.Coord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.coord
+}
+
+func (c *cursor) set(coord game.Coord) {
+	c.mu.Lock()
+	c.coord = coord
+	c.mu.Unlock()
+}
+
+// move shifts the cursor, wrapping around the board edges.
+func (c *cursor) move(dx, dy int) {
+	c.mu.Lock()
+	c.coord.X = (c.coord.X + dx + game.Size) % game.Size
+	c.coord.Y = (c.coord.Y + dy + game.Size) % game.Size
+	c.mu.Unlock()
+}
+
+func (c *cursor) show(visible bool) {
+	c.mu.Lock()
+	c.hidden = !visible
+	c.mu.Unlock()
+}
+
+// direction maps arrow keys, WASD and hjkl to a cursor move.
+func direction(ev tl.Event) (dx, dy int, ok bool) {
+	switch ev.Key {
+	case tl.KeyArrowUp:
+		return 0, -1, true
+	case tl.KeyArrowDown:
+		return 0, 1, true
+	case tl.KeyArrowLeft:
+		return -1, 0, true
+	case tl.KeyArrowRight:
+		return 1, 0, true
+	}
+	switch ev.Ch {
+	case 'w', 'W', 'k', 'K':
+		return 0, -1, true
+	case 's', 'S', 'j', 'J':
+		return 0, 1, true
+	case 'a', 'A', 'h', 'H':
+		return -1, 0, true
+	case 'd', 'D', 'l', 'L':
+		return 1, 0, true
+	}
+	return 0, 0, false
+}
+
+// steer handles a key press on the battle screen. Movement keys move the
+// cursor, a column letter followed by digits jumps to that cell and Enter
+// or Space returns the cell under the cursor when it wasn't shot yet.
+// Letters that are both a column and a movement key move the cursor
+// first, the digits typed next decide where it ends up.
+func (guiB *GuiBattle) steer(ev tl.Event) string {
+	switch {
+	case ev.Key == tl.KeyEnter || ev.Key == tl.KeySpace:
+		guiB.typed = ""
+		c := guiB.Cursor.at()
+		if guiB.shot(c.String()) {
+			guiB.ShouldFire.SetText(fmt.Sprintf("%s was already shot, pick another cell", c))
+			guiB.showTarget()
+			return ""
+		}
+		return c.String()
+	case ev.Key == tl.KeyBackspace || ev.Key == tl.KeyBackspace2:
+		if guiB.typed != "" {
+			guiB.typed = guiB.typed[:len(guiB.typed)-1]
+		}
+	case ev.Ch >= '0' && ev.Ch <= '9':
+		guiB.typeDigit(ev.Ch)
+	default:
+		dx, dy, move := direction(ev)
+		if move {
+			guiB.Cursor.move(dx, dy)
+		}
+		col := strings.ToUpper(string(ev.Ch))
+		switch {
+		case ev.Ch != 0 && strings.Contains("ABCDEFGHIJ", col):
+			guiB.typed = col
+		case move:
+			guiB.typed = ""
+		default:
+			return ""
+		}
+	}
+	guiB.showTarget()
+	return ""
+}
+
+// typeDigit adds a row digit to the typed coordinate. A digit that would
+// make the row invalid starts the row over.
+func (guiB *GuiBattle) typeDigit(d rune) {
+	if guiB.typed == "" {
+		return
+	}
+	for _, entry := range []string{guiB.typed + string(d), guiB.typed[:1] + string(d)} {
+		if c, err := game.ParseCoord(entry); err == nil {
+			guiB.typed = entry
+			guiB.Cursor.set(c)
+			return
+		}
+	}
+}
+
+// showTarget describes the cell under the cursor and any typed entry.
+func (guiB *GuiBattle) showTarget() {
+	c := guiB.Cursor.at()
+	text := fmt.Sprintf("Target: %-3s", c)
+	if guiB.typed != "" && guiB.typed != c.String() {
+		text = fmt.Sprintf("Target: %-3s typed: %s", c, guiB.typed)
+	}
+	if guiB.shot(c.String()) {
+		guiB.Target.SetText(text + " (already shot)")
+		guiB.Target.SetFgColor(gui.Red)
+		return
+	}
+	guiB.Target.SetText(text + " (" + targetHelp + ")")
+	guiB.Target.SetFgColor(gui.White)
+}
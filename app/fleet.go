@@ -0,0 +1,122 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/game"
+)
+
+var (
+	strategies = []game.Strategy{game.StrategyUniform, game.StrategySpread, game.StrategyClustered, game.StrategyAntiProbability}
+	edgeBiases = []game.EdgeBias{game.EdgeNeutral, game.EdgeAvoid, game.EdgeHug}
+)
+
+// renderGrid draws a board as termui styled text. marks overrides the
+// character and color of single cells, e.g. to highlight a cursor.
+func renderGrid(ships []game.Coord, marks map[game.Coord]string) string {
+	board := game.NewBoard(ships)
+	var sb strings.Builder
+	sb.WriteString("    ")
+	for x := 0; x < game.Size; x++ {
+		sb.WriteString(fmt.Sprintf("%c ", 'A'+x))
+	}
+	sb.WriteString("\n")
+	for y := 0; y < game.Size; y++ {
+		sb.WriteString(fmt.Sprintf("%3d ", y+1))
+		for x := 0; x < game.Size; x++ {
+			c := game.Coord{X: x, Y: y}
+			if m, ok := marks[c]; ok {
+				sb.WriteString(m + " ")
+				continue
+			}
+			if board.At(c) == game.CellShip {
+				sb.WriteString("[#](fg:green) ")
+			} else {
+				sb.WriteString("[~](fg:blue) ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+const (
+	randomFleetKeys = "r - reroll\nn - enter a seed\ns - change strategy\ne - change edge bias\nEnter - use this fleet\nEsc - back"
+	seedLen         = 20
+)
+
+// randomFleet shows generated layouts that can be rerolled and tuned
+// before being accepted. Typing in a seed shown earlier brings its layout
+// back. It reads the caller's event channel and returns nil when the
+// player backs out.
+func (a *App) randomFleet(uiEvents <-chan termui.Event) []string {
+	termui.Clear()
+	strategy, edge := 0, 0
+	seed := time.Now().UnixNano()
+	var fleet []game.Coord
+	note := ""
+	generate := func() {
+		g := game.NewGenerator(game.GeneratorOptions{Seed: seed, Strategy: strategies[strategy], Edge: edgeBiases[edge]})
+		fleet = g.Generate()
+	}
+	generate()
+
+	board := widgets.NewParagraph()
+	help := widgets.NewParagraph()
+	help.Title = "Keys"
+	help.SetRect(0, 15, 48, 24)
+	render := func() {
+		help.Text = randomFleetKeys
+		if note != "" {
+			help.Text += "\n" + note
+		}
+		board.Title = fmt.Sprintf("Random fleet: %s, %s (seed %d)", strategies[strategy], edgeBiases[edge], seed)
+		board.Text = renderGrid(fleet, nil)
+		board.SetRect(0, 0, 48, 14)
+		termui.Render(board, help)
+	}
+	render()
+
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		note = ""
+		switch ev.ID {
+		case "n", "N":
+			text := a.promptText(uiEvents, "Seed to generate the fleet from (Esc - back)", seedLen)
+			if text == "" {
+				break
+			}
+			n, err := strconv.ParseInt(text, 10, 64)
+			if err != nil || n == 0 {
+				note = fmt.Sprintf("[%s is not a seed](fg:red)", text)
+				break
+			}
+			seed = n
+			generate()
+		case "r", "R":
+			seed = time.Now().UnixNano()
+			generate()
+		case "s", "S":
+			strategy = (strategy + 1) % len(strategies)
+			generate()
+		case "e", "E":
+			edge = (edge + 1) % len(edgeBiases)
+			generate()
+		case "<Enter>":
+			termui.Clear()
+			return game.FormatCoords(fleet)
+		case "<Escape>":
+			termui.Clear()
+			return nil
+		}
+		render()
+	}
+}
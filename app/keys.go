@@ -0,0 +1,44 @@
+package app
+
+import (
+	"github.com/google/uuid"
+	tl "github.com/grupawp/termloop"
+)
+
+// keyListener is an invisible gui drawable passing key presses on the
+// battle screen on to us, gui.Board only reports mouse clicks.
+type keyListener struct {
+	id uuid.UUID
+	ch chan tl.Event
+}
+
+func newKeyListener() *keyListener {
+	return &keyListener{id: uuid.New(), ch: make(chan tl.Event, 16)}
+}
+
+func (k *keyListener) ID() uuid.UUID {
+	return k.id
+}
+
+func (k *keyListener) Drawables() []tl.Drawable {
+	return []tl.Drawable{k}
+}
+
+// Tick runs on the gui loop, so presses are dropped rather than blocking
+// it when nobody reads them.
+func (k *keyListener) Tick(ev tl.Event) {
+	if ev.Type != tl.EventKey {
+		return
+	}
+	select {
+	case k.ch <- ev:
+	default:
+	}
+}
+
+func (k *keyListener) Draw(*tl.Screen) {}
+
+// Events returns the key presses seen on the battle screen.
+func (k *keyListener) Events() <-chan tl.Event {
+	return k.ch
+}
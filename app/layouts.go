@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/client"
+	"main/game"
+	"main/layouts"
+)
+
+const layoutNameLen = 20
+
+func (a *App) loadLayouts() (*layouts.Library, error) {
+	if a.LayoutsPath != "" {
+		return layouts.Load(a.LayoutsPath)
+	}
+	return layouts.LoadDefault()
+}
+
+// promptText reads a line of text, returning "" on Escape.
+func (a *App) promptText(uiEvents <-chan termui.Event, title string, maxLen int) string {
+	termui.Clear()
+	input := widgets.NewParagraph()
+	input.Title = title
+	input.TextStyle = termui.NewStyle(termui.ColorYellow)
+	input.SetRect(0, 0, 80, 3)
+	termui.Render(input)
+
+	text := ""
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		switch ev.ID {
+		case "<Enter>":
+			termui.Clear()
+			return strings.TrimSpace(text)
+		case "<Escape>":
+			termui.Clear()
+			return ""
+		case "<Backspace>":
+			if len(text) > 0 {
+				text = text[:len(text)-1]
+			}
+		case "<Space>":
+			if len(text) < maxLen {
+				text += " "
+			}
+		default:
+			if len(ev.ID) == 1 && len(text) < maxLen {
+				text += ev.ID
+			}
+		}
+		input.Text = text
+		termui.Render(input)
+	}
+}
+
+// offerSaveLayout asks for a name and stores the fleet in the library.
+// An empty name skips saving.
+func (a *App) offerSaveLayout(uiEvents <-chan termui.Event, title string, fleet []string) {
+	a.saveLayout(a.promptText(uiEvents, title, layoutNameLen), fleet)
+}
+
+func (a *App) saveLayout(name string, fleet []string) {
+	if name == "" {
+		return
+	}
+	lib, err := a.loadLayouts()
+	if err != nil {
+		log.Printf("app saveLayout, a.loadLayouts(); %v", err)
+		return
+	}
+	if err := lib.Put(name, fleet); err != nil {
+		log.Printf("app saveLayout, lib.Put(); %v", err)
+		return
+	}
+	if err := lib.Save(); err != nil {
+		log.Printf("app saveLayout, lib.Save(); %v", err)
+	}
+}
+
+// askServerLayoutName asks, before a server game without our own fleet
+// starts, under which name to keep the fleet the server picks. Asking
+// once the game runs would eat into the first turn.
+func (a *App) askServerLayoutName(uiEvents <-chan termui.Event) {
+	a.serverLayout = a.promptText(uiEvents, "The server picks your fleet. Save it as? Enter a name or press Esc", layoutNameLen)
+}
+
+// saveServerLayout stores the fleet the server picked for g under the name
+// given in askServerLayoutName.
+func (a *App) saveServerLayout(g client.Game, fleet []string) {
+	name := a.serverLayout
+	a.serverLayout = ""
+	if len(g.Coords) != 0 || len(fleet) != game.FleetCells {
+		return
+	}
+	a.saveLayout(name, fleet)
+}
+
+// pickLayout lists saved layouts with a preview. Enter picks one, d deletes
+// it and Escape goes back returning nil.
+func (a *App) pickLayout(uiEvents <-chan termui.Event) []string {
+	lib, err := a.loadLayouts()
+	if err != nil {
+		log.Printf("app pickLayout, a.loadLayouts(); %v", err)
+		return nil
+	}
+	termui.Clear()
+	list := widgets.NewList()
+	list.Title = "Saved layouts (Enter - use, d - delete, Esc - back)"
+	list.SelectedRowStyle = termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
+	list.SetRect(0, 0, 40, gridHeight)
+	preview := widgets.NewParagraph()
+	preview.SetRect(41, 0, 41+gridWidth, gridHeight)
+
+	render := func() {
+		names := lib.Names()
+		list.Rows = names
+		if len(names) == 0 {
+			list.Rows = []string{"No saved layouts"}
+			preview.Text = ""
+		} else {
+			if list.SelectedRow >= len(names) {
+				list.SelectedRow = len(names) - 1
+			}
+			layout, _ := lib.Get(names[list.SelectedRow])
+			coords, _ := game.ParseCoords(layout.Coords)
+			preview.Title = layout.Name
+			preview.Text = renderGrid(coords, nil)
+		}
+		termui.Render(list, preview)
+	}
+	render()
+
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		names := lib.Names()
+		switch ev.ID {
+		case "<Down>":
+			list.ScrollDown()
+		case "<Up>":
+			list.ScrollUp()
+		case "<Enter>":
+			if len(names) == 0 {
+				continue
+			}
+			layout, err := lib.Get(names[list.SelectedRow])
+			if err != nil {
+				continue
+			}
+			termui.Clear()
+			return layout.Coords
+		case "d", "D":
+			if len(names) == 0 {
+				continue
+			}
+			if err := lib.Delete(names[list.SelectedRow]); err == nil {
+				if err := lib.Save(); err != nil {
+					log.Printf("app pickLayout, lib.Save(); %v", err)
+				}
+			}
+		case "<Escape>":
+			termui.Clear()
+			return nil
+		}
+		render()
+	}
+}
+
+func saveLayoutTitle(fleet []string) string {
+	return fmt.Sprintf("Save this %d cell fleet? Enter a name or press Esc", len(fleet))
+}
@@ -0,0 +1,261 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/client"
+)
+
+const (
+	leaderboardPageSize = 15
+	leaderboardWidth    = 90
+)
+
+var leaderboardColumns = []string{"Rank", "Nick", "Games", "Wins", "Points", "Win rate"}
+
+// leaderboard is the state of the stats table: sort column and direction,
+// nick filter, page and selected row within the page.
+type leaderboard struct {
+	stats     []client.Stats
+	own       string
+	sortCol   int
+	desc      bool
+	query     string
+	searching bool
+	page      int
+	selected  int
+}
+
+func winRate(s client.Stats) float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Games)
+}
+
+func (l *leaderboard) less(a, b client.Stats) bool {
+	switch l.sortCol {
+	case 1:
+		return strings.ToLower(a.Nick) < strings.ToLower(b.Nick)
+	case 2:
+		return a.Games < b.Games
+	case 3:
+		return a.Wins < b.Wins
+	case 4:
+		return a.Points < b.Points
+	case 5:
+		return winRate(a) < winRate(b)
+	}
+	return a.Rank < b.Rank
+}
+
+// rows returns the stats matching the search, sorted.
+func (l *leaderboard) rows() []client.Stats {
+	var rows []client.Stats
+	query := strings.ToLower(l.query)
+	for _, s := range l.stats {
+		if strings.Contains(strings.ToLower(s.Nick), query) {
+			rows = append(rows, s)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		if l.desc {
+			return l.less(rows[j], rows[i])
+		}
+		return l.less(rows[i], rows[j])
+	})
+	return rows
+}
+
+func (l *leaderboard) pages(n int) int {
+	if n == 0 {
+		return 1
+	}
+	return (n + leaderboardPageSize - 1) / leaderboardPageSize
+}
+
+// pageRows returns the rows on the current page, clamping page and
+// selection to what is left after filtering.
+func (l *leaderboard) pageRows() ([]client.Stats, int) {
+	rows := l.rows()
+	pages := l.pages(len(rows))
+	if l.page >= pages {
+		l.page = pages - 1
+	}
+	if l.page < 0 {
+		l.page = 0
+	}
+	start := l.page * leaderboardPageSize
+	end := start + leaderboardPageSize
+	if end > len(rows) {
+		end = len(rows)
+	}
+	page := rows[start:end]
+	if l.selected >= len(page) {
+		l.selected = len(page) - 1
+	}
+	if l.selected < 0 {
+		l.selected = 0
+	}
+	return page, pages
+}
+
+// Selected returns the highlighted row, false when nothing matches.
+func (l *leaderboard) Selected() (client.Stats, bool) {
+	page, _ := l.pageRows()
+	if len(page) == 0 {
+		return client.Stats{}, false
+	}
+	return page[l.selected], true
+}
+
+// sortBy sorts by column col, flipping the direction when it already is.
+// Numbers start from the highest value, names from A.
+func (l *leaderboard) sortBy(col int) {
+	if l.sortCol == col {
+		l.desc = !l.desc
+	} else {
+		l.sortCol = col
+		l.desc = col >= 2
+	}
+	l.page, l.selected = 0, 0
+}
+
+// key handles a key press and reports whether the screen should close.
+func (l *leaderboard) key(id string) bool {
+	if l.searching {
+		switch id {
+		case "<Enter>", "<Escape>":
+			l.searching = false
+		case "<Backspace>":
+			if len(l.query) > 0 {
+				l.query = l.query[:len(l.query)-1]
+			}
+		case "<Space>":
+			l.query += " "
+		default:
+			if len([]rune(id)) == 1 {
+				l.query += id
+			}
+		}
+		l.page, l.selected = 0, 0
+		return false
+	}
+	switch id {
+	case "<Down>":
+		l.selected++
+		if page, pages := l.pageRows(); l.selected >= len(page) && l.page < pages-1 {
+			l.page++
+			l.selected = 0
+		}
+	case "<Up>":
+		if l.selected == 0 && l.page > 0 {
+			l.page--
+			l.selected = leaderboardPageSize - 1
+		} else {
+			l.selected--
+		}
+	case "<Right>", "<PageDown>", "n":
+		l.page++
+		l.selected = 0
+	case "<Left>", "<PageUp>", "p":
+		l.page--
+		l.selected = 0
+	case "/":
+		l.searching = true
+	case "<Backspace>":
+		l.query = ""
+	case "1", "2", "3", "4", "5", "6":
+		l.sortBy(int(id[0] - '1'))
+	case "<Escape>", "q":
+		return true
+	}
+	l.pageRows()
+	return false
+}
+
+func (l *leaderboard) render(table *widgets.Table, help *widgets.Paragraph) {
+	page, pages := l.pageRows()
+	header := make([]string, len(leaderboardColumns))
+	for i, c := range leaderboardColumns {
+		header[i] = fmt.Sprintf("%d %s", i+1, c)
+		if i == l.sortCol {
+			arrow := "^"
+			if l.desc {
+				arrow = "v"
+			}
+			header[i] += " " + arrow
+		}
+	}
+	table.Rows = [][]string{header}
+	table.RowStyles = map[int]termui.Style{0: termui.NewStyle(termui.ColorWhite, termui.ColorClear, termui.ModifierBold)}
+	for i, s := range page {
+		table.Rows = append(table.Rows, []string{
+			fmt.Sprint(s.Rank), s.Nick, fmt.Sprint(s.Games), fmt.Sprint(s.Wins), fmt.Sprint(s.Points),
+			fmt.Sprintf("%.1f%%", 100*winRate(s)),
+		})
+		switch {
+		case i == l.selected:
+			table.RowStyles[i+1] = termui.NewStyle(termui.ColorBlack, termui.ColorGreen)
+		case l.own != "" && s.Nick == l.own:
+			table.RowStyles[i+1] = termui.NewStyle(termui.ColorYellow, termui.ColorClear, termui.ModifierBold)
+		}
+	}
+	if len(page) == 0 {
+		table.Rows = append(table.Rows, []string{"", "no players", "", "", "", ""})
+	}
+	table.Title = fmt.Sprintf("Leaderboard, page %d / %d", l.page+1, pages)
+	table.SetRect(0, 0, leaderboardWidth, len(table.Rows)+2)
+
+	search := l.query
+	if l.searching {
+		search += "_"
+	}
+	help.Text = fmt.Sprintf("Search: %s\n1-6 sort, / search, Backspace clear search, Left/Right page, Esc back", search)
+	help.SetRect(0, len(table.Rows)+2, leaderboardWidth, len(table.Rows)+6)
+	termui.Clear()
+	termui.Render(table, help)
+}
+
+// showLeaderboard shows the server stats as a sortable, searchable table
+// with our nick highlighted. It returns when the player goes back.
+func (a *App) showLeaderboard(ctx context.Context, uiEvents <-chan termui.Event) {
+	termui.Clear()
+	stats, err := a.Client.GetStats(ctx)
+	if err != nil {
+		log.Printf("app showLeaderboard, client.GetStats(); %v", err)
+	}
+	own := a.Nick
+	if own == "" && a.Session != nil {
+		own = a.Session.Nick
+	}
+	l := &leaderboard{stats: stats.Stats, own: own}
+	table := widgets.NewTable()
+	table.RowSeparator = false
+	table.ColumnWidths = []int{10, 30, 10, 10, 12, 16}
+	help := widgets.NewParagraph()
+	help.Border = false
+	l.render(table, help)
+
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		if ev.ID == "<Enter>" && !l.searching {
+			if st, ok := l.Selected(); ok {
+				a.showPlayerStats(ctx, a.Client, uiEvents, st.Nick, false)
+			}
+		} else if l.key(ev.ID) {
+			termui.Clear()
+			return
+		}
+		l.render(table, help)
+	}
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/client"
+)
+
+const (
+	lobbyRefresh  = 2 * time.Second
+	statusWaiting = "waiting"
+)
+
+// lobbyEntry is a player seen in the lobby. Left is set once they are no
+// longer listed.
+type lobbyEntry struct {
+	Nick   string
+	Status string
+	Left   bool
+}
+
+func (e lobbyEntry) challengeable() bool {
+	return !e.Left && e.Status == statusWaiting
+}
+
+// lobby keeps every player seen since the browser was opened, in the
+// order they showed up.
+type lobby struct {
+	entries     []lobbyEntry
+	onlyWaiting bool
+	selected    int
+	updated     time.Time
+	err         error
+}
+
+// update merges a fresh player list, marking players missing from it as
+// left.
+func (l *lobby) update(players client.PlayersStatus) {
+	seen := make(map[string]string, len(players))
+	for _, p := range players {
+		seen[p.Nick] = p.GameStatus
+	}
+	for i := range l.entries {
+		status, ok := seen[l.entries[i].Nick]
+		l.entries[i].Left = !ok
+		if ok {
+			l.entries[i].Status = status
+			delete(seen, l.entries[i].Nick)
+		}
+	}
+	for _, p := range players {
+		if _, ok := seen[p.Nick]; ok {
+			l.entries = append(l.entries, lobbyEntry{Nick: p.Nick, Status: p.GameStatus})
+		}
+	}
+	l.updated = time.Now()
+}
+
+func (l *lobby) visible() []lobbyEntry {
+	if !l.onlyWaiting {
+		return l.entries
+	}
+	var res []lobbyEntry
+	for _, e := range l.entries {
+		if e.challengeable() {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+func (l *lobby) render(list *widgets.List, info *widgets.Paragraph, msg string) {
+	entries := l.visible()
+	if l.selected >= len(entries) {
+		l.selected = len(entries) - 1
+	}
+	if l.selected < 0 {
+		l.selected = 0
+	}
+	list.Rows = list.Rows[:0]
+	for _, e := range entries {
+		switch {
+		case e.Left:
+			list.Rows = append(list.Rows, fmt.Sprintf("[%-20s left](fg:red)", e.Nick))
+		case e.challengeable():
+			list.Rows = append(list.Rows, fmt.Sprintf("%-20s %s", e.Nick, e.Status))
+		default:
+			list.Rows = append(list.Rows, fmt.Sprintf("[%-20s %s](fg:yellow)", e.Nick, e.Status))
+		}
+	}
+	if len(entries) == 0 {
+		list.Rows = append(list.Rows, "No players in the lobby yet")
+	}
+	list.SelectedRow = l.selected
+	filter := "all players"
+	if l.onlyWaiting {
+		filter = "waiting only"
+	}
+	list.Title = fmt.Sprintf("Lobby (%s)", filter)
+
+	status := "Refreshing..."
+	if !l.updated.IsZero() {
+		status = fmt.Sprintf("Updated %s ago", time.Since(l.updated).Round(time.Second))
+	}
+	if l.err != nil {
+		status = fmt.Sprintf("Refresh failed: %v", l.err)
+	}
+	if msg != "" {
+		status = msg
+	}
+	info.Text = status + "\nEnter challenge, f filter waiting, r refresh, Esc back to menu"
+	termui.Render(list, info)
+}
+
+type lobbyUpdate struct {
+	players client.PlayersStatus
+	err     error
+}
+
+// refreshLobby polls the lobby until ctx is done. A value on now forces
+// an immediate refresh.
+func refreshLobby(ctx context.Context, c client.GameAPI, updates chan<- lobbyUpdate, now <-chan struct{}) {
+	ticker := time.NewTicker(lobbyRefresh)
+	defer ticker.Stop()
+	for {
+		players, err := c.GetPlayers(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		select {
+		case updates <- lobbyUpdate{players: players, err: err}:
+		case <-ctx.Done():
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-now:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// getTarget shows the live lobby and returns the nick picked to be
+// challenged, or an empty string when the player backs out.
+func (a *App) getTarget(ctx context.Context, c client.GameAPI, uiEvents <-chan termui.Event) string {
+	termui.Clear()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	updates := make(chan lobbyUpdate)
+	now := make(chan struct{}, 1)
+	go refreshLobby(ctx, c, updates, now)
+
+	l := &lobby{onlyWaiting: true}
+	list := widgets.NewList()
+	list.SelectedRowStyle = termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
+	list.SetRect(0, 0, 50, 20)
+	info := widgets.NewParagraph()
+	info.SetRect(0, 20, 80, 24)
+	clock := time.NewTicker(time.Second)
+	defer clock.Stop()
+	msg := ""
+	l.render(list, info, msg)
+
+	for {
+		select {
+		case u := <-updates:
+			l.err = u.err
+			if u.err == nil {
+				l.update(u.players)
+			}
+		case <-clock.C:
+		case ev := <-uiEvents:
+			if ev.Type != termui.KeyboardEvent {
+				continue
+			}
+			msg = ""
+			switch ev.ID {
+			case "<Down>":
+				l.selected++
+			case "<Up>":
+				l.selected--
+			case "f", "F":
+				l.onlyWaiting = !l.onlyWaiting
+			case "r", "R":
+				select {
+				case now <- struct{}{}:
+				default:
+				}
+			case "<Enter>":
+				entries := l.visible()
+				if len(entries) == 0 {
+					continue
+				}
+				e := entries[l.selected]
+				if !e.challengeable() {
+					msg = fmt.Sprintf("%s cannot be challenged right now", e.Nick)
+					break
+				}
+				if a.showPlayerStats(ctx, c, uiEvents, e.Nick, true) {
+					return e.Nick
+				}
+			case "<Escape>":
+				termui.Clear()
+				return ""
+			}
+		}
+		l.render(list, info, msg)
+	}
+}
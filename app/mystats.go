@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/history"
+	"main/replay"
+)
+
+// accuracyWindow is the number of games averaged in the accuracy trend.
+const accuracyWindow = 5
+
+func (a *App) matchesPath() (string, error) {
+	if a.MatchesPath != "" {
+		return a.MatchesPath, nil
+	}
+	return history.DefaultPath()
+}
+
+// recordMatch adds a finished game to the local match history.
+func (a *App) recordMatch(r *replay.Replay, replayPath string) {
+	path, err := a.matchesPath()
+	if err != nil {
+		a.Ui.Log(fmt.Sprintf("app recordMatch, a.matchesPath(); %v", err))
+		return
+	}
+	if err := history.Append(path, history.FromReplay(r, replayPath)); err != nil {
+		a.Ui.Log(fmt.Sprintf("app recordMatch, history.Append(); %v", err))
+	}
+}
+
+// showMyStats shows statistics of our own games from the local history
+// until Enter or Escape is pressed.
+func (a *App) showMyStats(uiEvents <-chan termui.Event) {
+	termui.Clear()
+	var matches []history.Match
+	if path, err := a.matchesPath(); err == nil {
+		store, err := history.Load(path)
+		if err != nil {
+			log.Printf("app showMyStats, history.Load(); %v", err)
+		} else {
+			matches = store.Matches
+		}
+	}
+	s := history.Summarize(matches)
+
+	summary := widgets.NewParagraph()
+	summary.Title = "My stats (Esc - back)"
+	summary.Text = fmt.Sprintf(
+		"Games: %d\nWins: %d\nLosses: %d\nAbandoned: %d\nWin rate: %.1f%%\nAccuracy: %.1f%% (%d / %d)\n"+
+			"Average game length: %v\nAverage shots per game: %.1f\nLongest win streak: %d\nCurrent win streak: %d",
+		s.Games, s.Wins, s.Losses, s.Abandoned, 100*s.WinRate(), 100*s.TotalAccuracy(), s.Hits, s.Shots,
+		s.AvgDuration.Round(time.Second), s.AvgShots, s.LongestStreak, s.CurrentStreak)
+	summary.SetRect(0, 0, 50, 13)
+
+	opponents := widgets.NewTable()
+	opponents.Title = "Win rate by opponent"
+	opponents.RowSeparator = false
+	opponents.Rows = [][]string{{"Opponent", "Games", "Wins", "Win rate"}}
+	for _, o := range s.ByOpponent {
+		name := o.Opponent
+		if o.Bot {
+			name += " (bot)"
+		}
+		opponents.Rows = append(opponents.Rows, []string{name, fmt.Sprint(o.Games), fmt.Sprint(o.Wins), fmt.Sprintf("%.0f%%", 100*o.WinRate())})
+	}
+	opponents.RowStyles[0] = termui.NewStyle(termui.ColorWhite, termui.ColorClear, termui.ModifierBold)
+	opponents.SetRect(50, 0, 110, 13)
+
+	drawables := []termui.Drawable{summary, opponents}
+	if len(s.Accuracy) >= 2 {
+		trend := widgets.NewPlot()
+		trend.Title = fmt.Sprintf("Accuracy %% per game (white) and %d game average (green)", accuracyWindow)
+		trend.Data = [][]float64{percent(s.Accuracy), percent(history.MovingAverage(s.Accuracy, accuracyWindow))}
+		trend.LineColors = []termui.Color{termui.ColorWhite, termui.ColorGreen}
+		trend.MaxVal = 100
+		trend.SetRect(0, 13, 110, 30)
+		drawables = append(drawables, trend)
+	} else {
+		trend := widgets.NewParagraph()
+		trend.Title = "Accuracy trend"
+		trend.Text = "Play at least two games to see a trend."
+		trend.SetRect(0, 13, 110, 16)
+		drawables = append(drawables, trend)
+	}
+	termui.Render(drawables...)
+
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		switch ev.ID {
+		case "<Escape>", "<Enter>":
+			termui.Clear()
+			return
+		}
+	}
+}
+
+func percent(values []float64) []float64 {
+	res := make([]float64, len(values))
+	for i, v := range values {
+		res[i] = 100 * v
+	}
+	return res
+}
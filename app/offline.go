@@ -0,0 +1,50 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/bot"
+	"main/client"
+)
+
+// pickDifficulty lets the player choose how the offline bot shoots. It
+// returns false when the player backs out.
+func (a *App) pickDifficulty(uiEvents <-chan termui.Event) (bot.Difficulty, bool) {
+	termui.Clear()
+	list := widgets.NewList()
+	list.Title = "Choose bot difficulty"
+	for _, d := range bot.Difficulties {
+		list.Rows = append(list.Rows, fmt.Sprintf("%s - %s", d, d.Description()))
+	}
+	list.SelectedRowStyle = termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
+	list.SetRect(0, 0, 50, len(list.Rows)+2)
+	termui.Render(list)
+
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		switch ev.ID {
+		case "<Down>":
+			list.ScrollDown()
+		case "<Up>":
+			list.ScrollUp()
+		case "<Enter>":
+			termui.Clear()
+			return bot.Difficulties[list.SelectedRow], true
+		case "<Escape>":
+			termui.Clear()
+			return bot.Easy, false
+		}
+		termui.Render(list)
+	}
+}
+
+// isOffline reports whether c is a local game that needs no session.
+func isOffline(c client.GameAPI) bool {
+	_, ok := c.(*bot.LocalGame)
+	return ok
+}
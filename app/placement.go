@@ -0,0 +1,157 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/game"
+)
+
+const (
+	gridWidth   = 48
+	gridHeight  = 14
+	gridOffsetX = 5
+	gridOffsetY = 2
+	legalGhost  = "[#](fg:black,bg:green)"
+	badGhost    = "[#](fg:black,bg:red)"
+)
+
+// cursorMoves maps arrow, WASD and hjkl keys to cursor steps.
+var cursorMoves = map[string][2]int{
+	"<Left>": {-1, 0}, "a": {-1, 0}, "h": {-1, 0},
+	"<Right>": {1, 0}, "d": {1, 0}, "l": {1, 0},
+	"<Up>": {0, -1}, "w": {0, -1}, "k": {0, -1},
+	"<Down>": {0, 1}, "s": {0, 1}, "j": {0, 1},
+}
+
+// moveCursor moves c by one step of the given key, staying on the board.
+func moveCursor(c game.Coord, key string) (game.Coord, bool) {
+	d, ok := cursorMoves[key]
+	if !ok {
+		return c, false
+	}
+	if n := c.Add(d[0], d[1]); n.Valid() {
+		return n, true
+	}
+	return c, true
+}
+
+// gridCell translates a mouse click on a grid drawn by renderGrid at 0,0.
+func gridCell(m termui.Mouse) (game.Coord, bool) {
+	dx := m.X - gridOffsetX
+	c := game.Coord{X: dx / 2, Y: m.Y - gridOffsetY}
+	return c, dx >= 0 && c.Valid()
+}
+
+// placeShips walks through the fleet ship by ship, longest first, showing
+// a ghost of the next ship at the cursor. It returns nil when the player
+// backs out.
+func (a *App) placeShips(uiEvents <-chan termui.Event) []string {
+	termui.Clear()
+	p := game.NewPlacement()
+	cursor := game.Coord{}
+	horizontal := true
+	message := ""
+
+	grid := widgets.NewParagraph()
+	grid.SetRect(0, 0, gridWidth, gridHeight)
+	inventory := widgets.NewParagraph()
+	inventory.Title = "Ships left"
+	inventory.SetRect(gridWidth+1, 0, gridWidth+31, gridHeight)
+	help := widgets.NewParagraph()
+	help.Title = "Keys"
+	help.Text = "arrows/WASD/hjkl - move, click - place here\nr or space - rotate, Enter - place\nu - undo, y - redo, Esc - back"
+	help.SetRect(0, gridHeight+1, gridWidth+31, gridHeight+6)
+
+	render := func() {
+		marks := make(map[game.Coord]string)
+		ghost, legal := p.Ghost(cursor, horizontal)
+		for _, c := range ghost {
+			if legal {
+				marks[c] = legalGhost
+			} else {
+				marks[c] = badGhost
+			}
+		}
+		grid.Text = renderGrid(p.Coords(), marks)
+		if size, ok := p.Next(); ok {
+			orientation := "vertical"
+			if horizontal {
+				orientation = "horizontal"
+			}
+			grid.Title = fmt.Sprintf("Place %d-masted ship at %s, %s", size, cursor, orientation)
+		} else {
+			grid.Title = "Fleet complete - Enter to confirm"
+		}
+		inventory.Text = inventoryText(p.Remaining(), message)
+		termui.Render(grid, inventory, help)
+	}
+	render()
+
+	for {
+		ev := <-uiEvents
+		message = ""
+		switch ev.Type {
+		case termui.KeyboardEvent:
+			if next, ok := moveCursor(cursor, ev.ID); ok {
+				cursor = next
+				break
+			}
+			switch ev.ID {
+			case "r", "R", "<Space>":
+				horizontal = !horizontal
+			case "u", "U":
+				if !p.Undo() {
+					message = "Nothing to undo"
+				}
+			case "y", "Y", "<C-r>":
+				if !p.Redo() {
+					message = "Nothing to redo"
+				}
+			case "<Enter>":
+				if p.Done() {
+					termui.Clear()
+					return game.FormatCoords(p.Coords())
+				}
+				message = placeMessage(p.Place(cursor, horizontal))
+			case "<Escape>":
+				termui.Clear()
+				return nil
+			}
+		case termui.MouseEvent:
+			if ev.ID != "<MouseLeft>" {
+				continue
+			}
+			if c, ok := gridCell(ev.Payload.(termui.Mouse)); ok {
+				cursor = c
+				message = placeMessage(p.Place(cursor, horizontal))
+			}
+		}
+		render()
+	}
+}
+
+func placeMessage(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, game.ErrIllegalShip):
+		return "[Ship does not fit there](fg:red)"
+	default:
+		return err.Error()
+	}
+}
+
+func inventoryText(remaining map[int]int, message string) string {
+	var sb strings.Builder
+	for size := 4; size >= 1; size-- {
+		sb.WriteString(fmt.Sprintf("%d-masted: %d left  %s\n", size, remaining[size], strings.Repeat("#", size)))
+	}
+	if message != "" {
+		sb.WriteString("\n" + message)
+	}
+	return sb.String()
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/client"
+)
+
+// showPlayerStats shows the stats of one player. With challenge set Enter
+// confirms challenging them and true is returned; Escape always goes back.
+func (a *App) showPlayerStats(ctx context.Context, c client.GameAPI, uiEvents <-chan termui.Event, nick string, challenge bool) bool {
+	termui.Clear()
+	p := widgets.NewParagraph()
+	p.Title = fmt.Sprintf("Player %s (Esc - back)", nick)
+	if challenge {
+		p.Title = fmt.Sprintf("Player %s (Enter - challenge, Esc - back)", nick)
+	}
+	st, err := c.GetPlayerStats(ctx, nick)
+	switch {
+	case errors.Is(err, client.ErrPlayerNotFound):
+		p.Text = "No games played yet."
+	case err != nil:
+		p.Text = fmt.Sprintf("Could not load stats: %v", err)
+		p.TextStyle = termui.NewStyle(termui.ColorRed)
+	default:
+		p.Text = fmt.Sprintf("Rank: %d\nPoints: %d\nGames: %d\nWins: %d\nWin rate: %.1f%%",
+			st.Rank, st.Points, st.Games, st.Wins, 100*winRate(st))
+	}
+	p.SetRect(0, 0, 60, 8)
+	termui.Render(p)
+
+	for {
+		ev := <-uiEvents
+		if ev.Type != termui.KeyboardEvent {
+			continue
+		}
+		switch ev.ID {
+		case "<Enter>":
+			termui.Clear()
+			return challenge
+		case "<Escape>":
+			termui.Clear()
+			return false
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"main/replay"
+)
+
+func (a *App) historyDir() (string, error) {
+	if a.HistoryDir != "" {
+		return a.HistoryDir, nil
+	}
+	return replay.DefaultDir()
+}
+
+// startRecording begins the replay of the game on the battle screen,
+// carrying over the shots and opponent kind of a resumed session.
+func (a *App) startRecording(wpbot bool) {
+	r := replay.New()
+	r.Nick = a.Nick
+	r.Desc = a.Desc
+	r.Opponent = a.TargetNick
+	r.OppDesc = a.ODesc
+	r.Fleet = a.PlayerBoard
+	r.Bot = wpbot || isOffline(a.Client)
+	if a.Session != nil {
+		a.Session.mu.Lock()
+		r.Bot = r.Bot || a.Session.Bot
+		if !a.Session.Started.IsZero() {
+			r.Started = a.Session.Started
+		}
+		for _, s := range a.Session.Shots {
+			r.Shots = append(r.Shots, replay.Shot{Coord: s.Coord, Result: s.Result, Time: s.Time})
+		}
+		r.OppShots = append([]string(nil), a.Session.OppShots...)
+		a.Session.mu.Unlock()
+	}
+	a.mu.Lock()
+	a.Replay = r
+	a.mu.Unlock()
+}
+
+// finishRecording writes the replay to the history dir once the game is
+// over. Later calls do nothing.
+func (a *App) finishRecording(status string) {
+	a.mu.Lock()
+	r := a.Replay
+	a.Replay = nil
+	oppShots := a.Status.OppShots
+	a.mu.Unlock()
+	if r == nil {
+		return
+	}
+	r.OppShots = append([]string(nil), oppShots...)
+	r.Status = status
+	r.Ended = time.Now()
+	dir, err := a.historyDir()
+	if err != nil {
+		a.Ui.Log(fmt.Sprintf("app finishRecording, a.historyDir(); %v", err))
+		a.recordMatch(r, "")
+		return
+	}
+	path, err := r.Save(dir)
+	if err != nil {
+		a.Ui.Log(fmt.Sprintf("app finishRecording, replay.Save(); %v", err))
+	} else {
+		a.Ui.Log(fmt.Sprintf("Replay saved to %s", path))
+	}
+	a.recordMatch(r, path)
+}
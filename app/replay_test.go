@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	gui "github.com/grupawp/warships-gui/v2"
+	"main/client"
+	"main/history"
+	"main/replay"
+)
+
+var testFleet = []string{
+	"A1", "B1", "C1", "D1",
+	"A3", "B3", "C3", "E3", "F3", "G3",
+	"A5", "B5", "D5", "E5", "G5", "H5",
+	"A7", "C7", "E7", "G7",
+}
+
+// newTestBattle starts a game on api and builds its battle screen without
+// a terminal, keeping the session, replays and history in a temp dir.
+func newTestBattle(t *testing.T, api client.GameAPI) (*App, *GuiBattle) {
+	t.Helper()
+	dir := t.TempDir()
+	a := &App{
+		Client:      api,
+		Nick:        "ann",
+		TargetNick:  "bob",
+		SessionPath: filepath.Join(dir, "session.json"),
+		HistoryDir:  filepath.Join(dir, "replays"),
+		MatchesPath: filepath.Join(dir, "matches.json"),
+		Ui:          gui.NewGUI(false),
+	}
+	if _, err := a.initGame(context.Background(), api, client.Game{Nick: a.Nick, TargetNick: a.TargetNick, Coords: testFleet}); err != nil {
+		t.Fatal(err)
+	}
+	a.PlayerBoard = testFleet
+	guiB := a.buildBattlefield(a.Ui)
+	a.startRecording(false)
+	return a, guiB
+}
+
+// checkFiled asserts the game was filed exactly once with the given
+// result and shot count and that its session is gone.
+func checkFiled(t *testing.T, a *App, result string, shots int) {
+	t.Helper()
+	paths, err := replay.List(a.HistoryDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("replays %v, want one", paths)
+	}
+	r, err := replay.Load(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != result || len(r.Shots) != shots {
+		t.Errorf("replay status %s with %d shots, want %s with %d", r.Status, len(r.Shots), result, shots)
+	}
+	store, err := history.Load(a.MatchesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Matches) != 1 || store.Matches[0].Result != result {
+		t.Errorf("history %+v, want one %s match", store.Matches, result)
+	}
+	if _, err := os.Stat(a.SessionPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("session file still there: %v", err)
+	}
+}
+
+func TestFinishBattleOnce(t *testing.T) {
+	fake := client.NewFakeClient()
+	a, guiB := newTestBattle(t, fake)
+	fake.PushStatus(client.StatusResponse{GameStatus: "ended", LastGameStatus: replay.StatusWin, OppShots: []string{"A1"}})
+	if _, err := a.pollStatus(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	a.recordShot("J10", missRes, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.finishBattle(guiB, nil)
+		}()
+	}
+	wg.Wait()
+	if !guiB.finished() {
+		t.Fatal("battle not finished")
+	}
+	checkFiled(t, a, replay.StatusWin, 1)
+}
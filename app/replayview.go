@@ -0,0 +1,246 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	tl "github.com/grupawp/termloop"
+	gui "github.com/grupawp/warships-gui/v2"
+	"main/game"
+	"main/replay"
+)
+
+// replaySpeeds are the delays between shots the +/- keys step through.
+var replaySpeeds = []time.Duration{
+	2 * time.Second,
+	time.Second,
+	500 * time.Millisecond,
+	250 * time.Millisecond,
+	100 * time.Millisecond,
+}
+
+const replayHelp = "Left/Right step, Space play, +/- speed, number+Enter jump to turn, q quit"
+
+// replayView is the state of the replay viewer.
+type replayView struct {
+	guiB    *GuiBattle
+	r       *replay.Replay
+	fleet   []game.Coord
+	steps   []replay.Step
+	turns   []int
+	pos     int
+	playing bool
+	delay   time.Duration
+	jump    string
+}
+
+// ViewReplay shows a recorded game on the battle screen and blocks until
+// the viewer is closed. speed is the initial delay between shots, a second
+// when not positive.
+func (a *App) ViewReplay(r *replay.Replay, speed time.Duration) error {
+	fleet, err := game.ParseCoords(r.Fleet)
+	if err != nil {
+		return fmt.Errorf("app ViewReplay, game.ParseCoords(); %w", err)
+	}
+	ui, err := a.makeUI()
+	if err != nil {
+		return fmt.Errorf("app ViewReplay, a.makeUI(); %w", err)
+	}
+	a.Ui = ui
+	a.Nick, a.Desc = r.Nick, r.Desc
+	a.TargetNick, a.ODesc = r.Opponent, r.OppDesc
+
+	v := &replayView{guiB: a.buildBattlefield(ui), r: r, fleet: fleet, steps: r.Steps(), delay: speed}
+	v.turns = replay.TurnStarts(v.steps)
+	if v.delay <= 0 {
+		v.delay = time.Second
+	}
+	v.guiB.Autopilot.SetText("")
+	v.guiB.Ui.Remove(v.guiB.Cursor)
+	v.guiB.Ui.Remove(v.guiB.Target)
+	v.guiB.Exit.SetText(replayHelp)
+	v.render()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go v.run(ctx, cancel)
+	ui.Start(ctx, nil)
+	return nil
+}
+
+func (v *replayView) run(ctx context.Context, cancel context.CancelFunc) {
+	for {
+		var tick <-chan time.Time
+		if v.playing {
+			tick = time.After(v.delay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+			v.seek(v.pos + 1)
+			if v.pos == len(v.steps) {
+				v.playing = false
+			}
+		case ev := <-v.guiB.Keys.Events():
+			if ev.Ch == 'q' || ev.Ch == 'Q' {
+				cancel()
+				return
+			}
+			v.key(ev)
+		}
+		v.render()
+	}
+}
+
+// fasterSpeed returns the longest preset delay below d, d when there is none.
+func fasterSpeed(d time.Duration) time.Duration {
+	for _, s := range replaySpeeds {
+		if s < d {
+			return s
+		}
+	}
+	return d
+}
+
+// slowerSpeed returns the shortest preset delay above d, d when there is none.
+func slowerSpeed(d time.Duration) time.Duration {
+	for i := len(replaySpeeds) - 1; i >= 0; i-- {
+		if replaySpeeds[i] > d {
+			return replaySpeeds[i]
+		}
+	}
+	return d
+}
+
+func (v *replayView) key(ev tl.Event) {
+	switch {
+	case ev.Key == tl.KeyArrowRight || ev.Ch == 'l':
+		v.seek(v.pos + 1)
+	case ev.Key == tl.KeyArrowLeft || ev.Ch == 'h':
+		v.seek(v.pos - 1)
+	case ev.Key == tl.KeyHome:
+		v.seek(0)
+	case ev.Key == tl.KeyEnd:
+		v.seek(len(v.steps))
+	case ev.Key == tl.KeySpace:
+		if v.pos == len(v.steps) {
+			v.seek(0)
+		}
+		v.playing = !v.playing
+	case ev.Ch == '+' || ev.Ch == '=':
+		v.delay = fasterSpeed(v.delay)
+	case ev.Ch == '-':
+		v.delay = slowerSpeed(v.delay)
+	case ev.Ch >= '0' && ev.Ch <= '9':
+		if len(v.jump) < 3 {
+			v.jump += string(ev.Ch)
+		}
+	case ev.Key == tl.KeyBackspace || ev.Key == tl.KeyBackspace2:
+		if len(v.jump) > 0 {
+			v.jump = v.jump[:len(v.jump)-1]
+		}
+	case ev.Key == tl.KeyEnter:
+		if n, err := strconv.Atoi(v.jump); err == nil {
+			v.seekTurn(n)
+		}
+		v.jump = ""
+	}
+}
+
+func (v *replayView) seek(pos int) {
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(v.steps) {
+		pos = len(v.steps)
+	}
+	v.pos = pos
+}
+
+// seekTurn moves to the end of turn n, counted from 1; 0 is the start.
+func (v *replayView) seekTurn(n int) {
+	if n < len(v.turns) {
+		v.seek(v.turns[n])
+		return
+	}
+	v.seek(len(v.steps))
+}
+
+// turn returns the turn the last shown step belongs to.
+func (v *replayView) turn() int {
+	n := 0
+	for _, start := range v.turns {
+		if start < v.pos {
+			n++
+		}
+	}
+	return n
+}
+
+// render rebuilds both boards and counters from the first pos steps.
+func (v *replayView) render() {
+	guiB := v.guiB
+	own := game.NewBoard(v.fleet)
+	target := &game.Board{}
+	var shots, hits, oppShots, oppHits int
+	for _, s := range v.steps[:v.pos] {
+		c, err := game.ParseCoord(s.Coord)
+		if err != nil {
+			continue
+		}
+		hit := s.Result == hitRes || s.Result == sunkRes
+		if s.Ours {
+			shots++
+			if hit {
+				hits++
+				target.Set(c, game.CellHit)
+			} else {
+				target.Set(c, game.CellMiss)
+			}
+			if s.Result == sunkRes {
+				target.MarkSunk(c)
+			}
+		} else {
+			oppShots++
+			if hit {
+				oppHits++
+			}
+			own.Fire(c)
+		}
+	}
+	guiB.PlayerBoardStates = toStates(own)
+	guiB.OpponentBoardStates = toStates(target)
+	guiB.PlayerBoard.SetStates(guiB.PlayerBoardStates)
+	guiB.OpponentBoard.SetStates(guiB.OpponentBoardStates)
+	guiB.PlayerAccuracy.SetText(fmt.Sprintf("Accuracy: %v / %v", hits, shots))
+	guiB.OpponentAccuracy.SetText(fmt.Sprintf("Accuracy: %v / %v", oppHits, oppShots))
+
+	guiB.Timer.SetText(fmt.Sprintf("Turn: %d / %d, shot: %d / %d", v.turn(), len(v.turns), v.pos, len(v.steps)))
+	state := "Paused"
+	if v.playing {
+		state = "Playing"
+	}
+	status := fmt.Sprintf("%s, %v per shot", state, v.delay)
+	if v.jump != "" {
+		status = fmt.Sprintf("Jump to turn: %s", v.jump)
+	}
+	guiB.ShouldFire.SetText(status)
+	guiB.ShouldFire.SetFgColor(gui.White)
+
+	guiB.ShotResult.SetText("")
+	guiB.OppShotResult.SetText("")
+	if v.pos > 0 {
+		last := v.steps[v.pos-1]
+		if last.Ours {
+			guiB.ShotResult.SetText(fmt.Sprintf("%s, %s on %s", v.r.Nick, last.Result, last.Coord))
+		} else {
+			guiB.OppShotResult.SetText(fmt.Sprintf("%s, %s on %s", v.r.Opponent, last.Result, last.Coord))
+		}
+	}
+	if v.pos == len(v.steps) && v.r.Status != "" {
+		guiB.ShouldFire.SetText(fmt.Sprintf("%s, game result: %s", status, v.r.Status))
+	}
+}
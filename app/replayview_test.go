@@ -0,0 +1,28 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplaySpeedKeys(t *testing.T) {
+	tests := []struct {
+		delay  time.Duration
+		faster time.Duration
+		slower time.Duration
+	}{
+		{delay: 3 * time.Second, faster: 2 * time.Second, slower: 3 * time.Second},
+		{delay: time.Second, faster: 500 * time.Millisecond, slower: 2 * time.Second},
+		{delay: 700 * time.Millisecond, faster: 500 * time.Millisecond, slower: time.Second},
+		{delay: 100 * time.Millisecond, faster: 100 * time.Millisecond, slower: 250 * time.Millisecond},
+		{delay: 10 * time.Millisecond, faster: 10 * time.Millisecond, slower: 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := fasterSpeed(tt.delay); got != tt.faster {
+			t.Errorf("fasterSpeed(%v) = %v, want %v", tt.delay, got, tt.faster)
+		}
+		if got := slowerSpeed(tt.delay); got != tt.slower {
+			t.Errorf("slowerSpeed(%v) = %v, want %v", tt.delay, got, tt.slower)
+		}
+	}
+}
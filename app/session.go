@@ -0,0 +1,346 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	gui "github.com/grupawp/warships-gui/v2"
+	"main/client"
+	"main/game"
+)
+
+const (
+	configDirName   = "statki"
+	sessionFileName = "session.json"
+)
+
+// ShotRecord is a single shot fired by us and the result the server gave.
+type ShotRecord struct {
+	Coord  string    `json:"coord"`
+	Result string    `json:"result"`
+	Time   time.Time `json:"time"`
+}
+
+// Session is the unfinished game persisted to disk so it can be resumed
+// after a crash or restart.
+type Session struct {
+	Token    string       `json:"token"`
+	Nick     string       `json:"nick"`
+	Desc     string       `json:"desc"`
+	Opponent string       `json:"opponent"`
+	OppDesc  string       `json:"opp_desc"`
+	Bot      bool         `json:"bot"`
+	Board    []string     `json:"board"`
+	Shots    []ShotRecord `json:"shots"`
+	OppShots []string     `json:"opp_shots"`
+	Started  time.Time    `json:"started"`
+
+	mu   sync.Mutex
+	path string
+	done bool
+}
+
+// configDir returns the statki directory under the user config dir.
+func configDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("configDir: os.UserConfigDir: %w", err)
+	}
+	return filepath.Join(dir, configDirName), nil
+}
+
+func (a *App) sessionPath() (string, error) {
+	if a.SessionPath != "" {
+		return a.SessionPath, nil
+	}
+	dir, err := configDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sessionFileName), nil
+}
+
+// loadSession reads the session file, returning nil when there is none.
+func loadSession(path string) (*Session, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loadSession: os.ReadFile: %w", err)
+	}
+	var s Session
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("loadSession: json.Unmarshal: %w", err)
+	}
+	s.path = path
+	return &s, nil
+}
+
+// save writes the session atomically.
+func (s *Session) save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("session save: os.MkdirAll: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("session save: json.Marshal: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("session save: os.WriteFile: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("session save: os.Rename: %w", err)
+	}
+	return nil
+}
+
+func (s *Session) remove() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("session remove: %w", err)
+	}
+	return nil
+}
+
+// initGame starts a game on the server and persists the new session.
+func (a *App) initGame(ctx context.Context, c client.GameAPI, game client.Game) (client.Game, error) {
+	game, err := c.InitGame(ctx, game)
+	if err != nil {
+		return game, err
+	}
+	if isOffline(c) {
+		return game, nil
+	}
+	path, err := a.sessionPath()
+	if err != nil {
+		log.Printf("app initGame, a.sessionPath(); %v", err)
+		return game, nil
+	}
+	a.Session = &Session{
+		Token:   c.GetToken(),
+		Nick:    game.Nick,
+		Desc:    game.Desc,
+		Bot:     game.WPBot,
+		Board:   game.Coords,
+		Started: time.Now(),
+		path:    path,
+	}
+	if err := a.Session.save(); err != nil {
+		log.Printf("app initGame, session.save(); %v", err)
+	}
+	return game, nil
+}
+
+// updateSession stores the details known once the battle starts.
+func (a *App) updateSession() {
+	if a.Session == nil {
+		return
+	}
+	a.Session.mu.Lock()
+	a.Session.Nick = a.Nick
+	a.Session.Desc = a.Desc
+	a.Session.Opponent = a.TargetNick
+	a.Session.OppDesc = a.ODesc
+	a.Session.Board = a.PlayerBoard
+	a.Session.mu.Unlock()
+	if err := a.Session.save(); err != nil {
+		a.Ui.Log(fmt.Sprintf("app updateSession, session.save(); %v", err))
+	}
+}
+
+// recordShot appends our shot and the opponent shots seen so far.
+func (a *App) recordShot(coord, result string, oppShots []string) {
+	a.mu.Lock()
+	if a.Replay != nil {
+		a.Replay.AddShot(coord, result)
+	}
+	a.mu.Unlock()
+	if a.Session == nil {
+		return
+	}
+	a.Session.mu.Lock()
+	a.Session.Shots = append(a.Session.Shots, ShotRecord{Coord: coord, Result: result, Time: time.Now()})
+	a.Session.OppShots = append([]string(nil), oppShots...)
+	a.Session.mu.Unlock()
+	if err := a.Session.save(); err != nil {
+		a.Ui.Log(fmt.Sprintf("app recordShot, session.save(); %v", err))
+	}
+}
+
+// clearSession deletes the session file of a finished or abandoned game.
+// Later saves of the same session are ignored.
+func (a *App) clearSession() {
+	if a.Session == nil {
+		return
+	}
+	if err := a.Session.remove(); err != nil {
+		log.Printf("app clearSession; %v", err)
+	}
+}
+
+// resumeSession looks for an unfinished game and asks whether to resume it.
+// It returns true when a.Client now holds the token of a game to continue.
+func (a *App) resumeSession(ctx context.Context) bool {
+	path, err := a.sessionPath()
+	if err != nil {
+		return false
+	}
+	s, err := loadSession(path)
+	if err != nil {
+		log.Printf("app resumeSession, loadSession(); %v", err)
+		return false
+	}
+	if s == nil || s.Token == "" {
+		return false
+	}
+	a.Session = s
+	a.Client.SetToken(s.Token)
+	status, err := a.Client.GetStatus(ctx)
+	for err != nil && !gameGone(err) {
+		// the game may still be running, keep the file for a later start
+		if !a.confirm(fmt.Sprintf("Could not check the unfinished game of %s: %v. Try again?", s.Nick, err)) {
+			a.Client.SetToken("")
+			a.Session = nil
+			return false
+		}
+		status, err = a.Client.GetStatus(ctx)
+	}
+	if err != nil || status.GameStatus == "ended" {
+		a.Client.SetToken("")
+		a.clearSession()
+		a.Session = nil
+		return false
+	}
+	if !a.askResume(s, status) {
+		if err := a.abandon(); err != nil {
+			log.Printf("app resumeSession, a.abandon(); %v", err)
+		}
+		a.Client.SetToken("")
+		a.clearSession()
+		a.Session = nil
+		return false
+	}
+	a.Status = status
+	if status.GameStatus == "waiting" || status.GameStatus == "waiting_wpbot" {
+		if err := termui.Init(); err != nil {
+			log.Fatalf("Failed to initialize termui 53: %v", err)
+		}
+		res := a.waitForOpponent(ctx, termui.PollEvents(), false)
+		termui.Close()
+		if res != waitStarted {
+			if err := a.abandon(); err != nil {
+				log.Printf("app resumeSession, a.abandon(); %v", err)
+			}
+			a.Client.SetToken("")
+			a.Session = nil
+			return false
+		}
+	}
+	return true
+}
+
+func (a *App) askResume(s *Session, status client.StatusResponse) bool {
+	opponent := status.Opponent
+	if opponent == "" {
+		opponent = "an opponent"
+	}
+	return a.confirm(fmt.Sprintf("Resume unfinished game of %s against %s (%s)?", s.Nick, opponent, status.GameStatus))
+}
+
+// confirm asks a yes or no question, Escape answers no.
+func (a *App) confirm(title string) bool {
+	if err := termui.Init(); err != nil {
+		log.Fatalf("Failed to initialize termui 51: %v", err)
+	}
+	defer termui.Close()
+
+	options := []string{"Yes", "No"}
+	list := widgets.NewList()
+	list.Title = title
+	list.Rows = options
+	list.SelectedRowStyle = termui.NewStyle(termui.ColorGreen, termui.ColorBlack)
+	a.triggerResize(list)
+
+	uiEvents := termui.PollEvents()
+	for {
+		ev := <-uiEvents
+		switch ev.Type {
+		case termui.KeyboardEvent:
+			switch ev.ID {
+			case "<Down>":
+				list.ScrollDown()
+			case "<Up>":
+				list.ScrollUp()
+			case "<Enter>":
+				termui.Clear()
+				return options[list.SelectedRow] == "Yes"
+			case "<Escape>":
+				termui.Clear()
+				return false
+			}
+			termui.Render(list)
+		case termui.ResizeEvent:
+			payload := ev.Payload.(termui.Resize)
+			termui.Clear()
+			list.SetRect(0, 0, payload.Width, payload.Height)
+			termui.Render(list)
+		}
+	}
+}
+
+// restoreBattle rebuilds both boards from the persisted shots and the
+// opponent shots reported by the server.
+func (a *App) restoreBattle(guiB *GuiBattle) {
+	for _, shot := range a.Status.OppShots {
+		c, err := game.ParseCoord(shot)
+		if err != nil {
+			continue
+		}
+		if a.contains(shot, a.PlayerBoard) {
+			guiB.PlayerBoardStates[c.X][c.Y] = gui.Hit
+		} else {
+			guiB.PlayerBoardStates[c.X][c.Y] = gui.Miss
+		}
+	}
+	guiB.PlayerBoard.SetStates(guiB.PlayerBoardStates)
+	if a.Session == nil {
+		return
+	}
+	for _, shot := range a.Session.Shots {
+		c, err := game.ParseCoord(shot.Coord)
+		if err != nil {
+			continue
+		}
+		switch shot.Result {
+		case hitRes, sunkRes:
+			guiB.OpponentBoardStates[c.X][c.Y] = gui.Hit
+		case missRes:
+			guiB.OpponentBoardStates[c.X][c.Y] = gui.Miss
+		}
+	}
+	for _, shot := range a.Session.Shots {
+		if c, err := game.ParseCoord(shot.Coord); err == nil && shot.Result == sunkRes {
+			a.markSunk(guiB, c)
+		}
+	}
+	guiB.OpponentBoard.SetStates(guiB.OpponentBoardStates)
+	guiB.showTarget()
+}
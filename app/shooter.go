@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	gui "github.com/grupawp/warships-gui/v2"
+	"main/bot"
+	"main/game"
+)
+
+const (
+	autopilotDelay = 600 * time.Millisecond
+	autopilotKey   = 'p'
+)
+
+// Shooter picks the cell our side fires at next. Aim returns an empty
+// string when ctx is done before a cell was picked.
+type Shooter interface {
+	Aim(ctx context.Context, guiB *GuiBattle) string
+}
+
+// humanShooter waits for a click on a cell not shot yet. Keyboard
+// targeting is handled by App.aim, which reads the key presses.
+type humanShooter struct{}
+
+func (humanShooter) Aim(ctx context.Context, guiB *GuiBattle) string {
+	for {
+		char := guiB.OpponentBoard.Listen(ctx)
+		if char == "" {
+			return ""
+		}
+		if c, err := game.ParseCoord(char); err == nil {
+			guiB.Cursor.set(c)
+		}
+		if !guiB.shot(char) {
+			return char
+		}
+		guiB.ShouldFire.SetText("You can't fire there!")
+		guiB.showTarget()
+	}
+}
+
+// aiShooter lets a bot strategy play our side from the opponent board
+// states, waiting a moment before each shot so it can be followed.
+type aiShooter struct {
+	shooter bot.Shooter
+}
+
+func newAIShooter(d bot.Difficulty) *aiShooter {
+	return &aiShooter{shooter: bot.New(d, rand.New(rand.NewSource(time.Now().UnixNano())))}
+}
+
+func (s *aiShooter) Aim(ctx context.Context, guiB *GuiBattle) string {
+	select {
+	case <-ctx.Done():
+		return ""
+	case <-time.After(autopilotDelay):
+	}
+	tracker := bot.TrackerFromBoard(toBoard(guiB.OpponentBoardStates))
+	return s.shooter.Shoot(tracker).String()
+}
+
+// shooter returns who fires for us right now.
+func (a *App) shooter() Shooter {
+	if a.Autopilot {
+		return newAIShooter(a.AutopilotLevel)
+	}
+	return humanShooter{}
+}
+
+// aim asks the current shooter for a target. Pressing the autopilot key
+// meanwhile switches between the player and the bot without losing the
+// turn, other keys steer the targeting cursor while we play ourselves.
+// It gives up with an empty string once the game is over.
+func (a *App) aim(ctx context.Context, guiB *GuiBattle) string {
+	for {
+		aimCtx, cancel := context.WithCancel(ctx)
+		done := make(chan string, 1)
+		go func(s Shooter) {
+			done <- s.Aim(aimCtx, guiB)
+		}(a.shooter())
+	wait:
+		for {
+			select {
+			case char := <-done:
+				cancel()
+				return char
+			case <-guiB.over:
+				cancel()
+				<-done
+				return ""
+			case ev := <-guiB.Keys.Events():
+				if ev.Ch != autopilotKey && ev.Ch != autopilotKey-'a'+'A' {
+					if a.Autopilot {
+						continue
+					}
+					char := guiB.steer(ev)
+					if char == "" {
+						continue
+					}
+					cancel()
+					<-done
+					return char
+				}
+				cancel()
+				a.Autopilot = !a.Autopilot
+				a.showAutopilot(guiB)
+				if char := <-done; char != "" {
+					return char
+				}
+				break wait
+			}
+		}
+	}
+}
+
+func (a *App) showAutopilot(guiB *GuiBattle) {
+	guiB.Cursor.show(!a.Autopilot)
+	if a.Autopilot {
+		guiB.Autopilot.SetText("Autopilot: on (P to take over)")
+		guiB.Autopilot.SetFgColor(gui.Green)
+		return
+	}
+	guiB.Autopilot.SetText("Autopilot: off (P to enable)")
+	guiB.Autopilot.SetFgColor(gui.White)
+}
+
+// shot reports whether the opponent cell was already fired at.
+func (guiB *GuiBattle) shot(char string) bool {
+	c, err := game.ParseCoord(char)
+	if err != nil {
+		return false
+	}
+	state := guiB.OpponentBoardStates[c.X][c.Y]
+	return state == gui.Hit || state == gui.Miss
+}
+
+func (a *App) menuTitle() string {
+	if a.Autopilot {
+		return fmt.Sprintf("Choose an Option (autopilot: %s)", a.AutopilotLevel)
+	}
+	return "Choose an Option"
+}
@@ -1,12 +1,18 @@
 package app
 
 import (
+	"sync"
+	"time"
+
 	gui "github.com/grupawp/warships-gui/v2"
+	"main/bot"
 	"main/client"
+	"main/replay"
 )
 
 type App struct {
-	Client      *client.Client
+	Client      client.GameAPI
+	NewAPI      func() client.GameAPI
 	PlayerBoard []string
 	Nick        string
 	TargetNick  string
@@ -14,6 +20,24 @@ type App struct {
 	Desc        string
 	ODesc       string
 	Ui          *gui.GUI
+	Session     *Session
+	SessionPath string
+	LayoutsPath string
+	HistoryDir  string
+	MatchesPath string
+	// BotOfferAfter is how long to wait for an opponent before offering
+	// a bot game, a minute when zero.
+	BotOfferAfter time.Duration
+	Replay        *replay.Replay
+	// Autopilot lets a bot of AutopilotLevel fire for us.
+	Autopilot      bool
+	AutopilotLevel bot.Difficulty
+
+	// serverLayout is the name to save the fleet the server picks under.
+	serverLayout string
+
+	// mu guards Status and Replay once the battle and timer loops run.
+	mu sync.Mutex
 }
 type GuiBattle struct {
 	PlayerBoard         *gui.Board
@@ -29,5 +53,14 @@ type GuiBattle struct {
 	Timer               *gui.Text
 	ShotResult          *gui.Text
 	OppShotResult       *gui.Text
+	Autopilot           *gui.Text
+	Keys                *keyListener
+	Cursor              *cursor
+	Target              *gui.Text
 	Ui                  *gui.GUI
+
+	typed string
+	// over is closed by finishBattle once the game is filed.
+	over    chan struct{}
+	endOnce sync.Once
 }
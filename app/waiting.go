@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"main/client"
+)
+
+const (
+	defaultBotOffer = time.Minute
+	// lobbyKeepAlive is how often the lobby registration is refreshed,
+	// the server drops players it has not heard from for a while.
+	lobbyKeepAlive = 10 * time.Second
+)
+
+type waitResult int
+
+const (
+	waitStarted waitResult = iota
+	waitCancelled
+	waitSwitchBot
+)
+
+func (a *App) botOffer() time.Duration {
+	if a.BotOfferAfter > 0 {
+		return a.BotOfferAfter
+	}
+	return defaultBotOffer
+}
+
+// waitForOpponent shows the waiting room until the game starts. Escape
+// gives up, and once the bot offer time has passed b asks for a bot game
+// instead when offerBot is set.
+func (a *App) waitForOpponent(ctx context.Context, uiEvents <-chan termui.Event, offerBot bool) waitResult {
+	termui.Clear()
+	start := time.Now()
+	lastRefresh := start
+	var refreshErr, statusErr error
+
+	msg := waitingParagraph()
+	render := func() {
+		elapsed := time.Since(start).Round(time.Second)
+		dots := int(elapsed/time.Second)%3 + 1
+		text := fmt.Sprintf("[Waiting for an opponent%-3s](fg:yellow)\nElapsed: %v\n", dotsText(dots), elapsed)
+		if statusErr != nil {
+			text += fmt.Sprintf("[Status check failed, retrying: %v](fg:red)\n", statusErr)
+		}
+		if refreshErr != nil {
+			text += fmt.Sprintf("[Lobby refresh failed: %v](fg:red)\n", refreshErr)
+		} else {
+			text += fmt.Sprintf("Lobby refreshed %v ago\n", time.Since(lastRefresh).Round(time.Second))
+		}
+		if offerBot && elapsed >= a.botOffer() {
+			text += "[No opponent yet, press b to play with the bot instead](fg:green)\n"
+		}
+		text += "Esc - abandon and go back to the menu"
+		msg.Text = text
+		termui.Render(msg)
+	}
+
+	ticker := time.NewTicker(waitingTime)
+	defer ticker.Stop()
+	for {
+		status, err := a.Client.GetStatus(ctx)
+		switch {
+		case err == nil:
+			statusErr = nil
+			a.Status = status
+			if status.GameStatus != "waiting" && status.GameStatus != "waiting_wpbot" {
+				termui.Clear()
+				return waitStarted
+			}
+		case ctx.Err() != nil:
+			return waitCancelled
+		case gameGone(err):
+			log.Printf("app waitForOpponent, c.GetStatus(); %v", err)
+			termui.Clear()
+			return waitCancelled
+		default:
+			// rate limits and network errors, try again on the next tick
+			statusErr = err
+		}
+		if time.Since(lastRefresh) >= lobbyKeepAlive {
+			refreshErr = a.Client.Refresh(ctx)
+			lastRefresh = time.Now()
+		}
+		render()
+
+		select {
+		case <-ctx.Done():
+			return waitCancelled
+		case <-ticker.C:
+		case ev := <-uiEvents:
+			if ev.Type != termui.KeyboardEvent {
+				continue
+			}
+			switch ev.ID {
+			case "<Escape>":
+				termui.Clear()
+				return waitCancelled
+			case "b", "B":
+				if offerBot && time.Since(start) >= a.botOffer() {
+					termui.Clear()
+					return waitSwitchBot
+				}
+			}
+		}
+	}
+}
+
+func dotsText(n int) string {
+	return "..."[:n]
+}
+
+func waitingParagraph() *widgets.Paragraph {
+	msg := widgets.NewParagraph()
+	msg.Title = "Waiting room"
+	msg.SetRect(0, 0, 60, 8)
+	return msg
+}
+
+// restartGame starts g again, retrying until it succeeds or Escape is
+// pressed.
+func (a *App) restartGame(ctx context.Context, c client.GameAPI, g client.Game, uiEvents <-chan termui.Event) (client.Game, bool) {
+	msg := waitingParagraph()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		started, err := a.initGame(ctx, c, g)
+		if err == nil {
+			return started, true
+		}
+		if ctx.Err() != nil {
+			return g, false
+		}
+		msg.Text = fmt.Sprintf("[Could not start the game, retrying: %v](fg:red)\nEsc - go back to the menu", err)
+		termui.Render(msg)
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				return g, false
+			case <-ticker.C:
+				waiting = false
+			case ev := <-uiEvents:
+				if ev.Type == termui.KeyboardEvent && ev.ID == "<Escape>" {
+					termui.Clear()
+					return g, false
+				}
+			}
+		}
+	}
+}
+
+// waitRoom waits for the game g to start. When the player gives up the
+// game is abandoned and false returned; switching to the bot abandons it
+// and starts g again against the bot.
+func (a *App) waitRoom(ctx context.Context, c client.GameAPI, g client.Game, uiEvents <-chan termui.Event) (client.Game, bool) {
+	for {
+		switch a.waitForOpponent(ctx, uiEvents, !g.WPBot) {
+		case waitStarted:
+			return g, true
+		case waitCancelled:
+			if err := a.abandon(); err != nil {
+				log.Printf("app waitRoom, a.abandon(); %v", err)
+			}
+			return g, false
+		case waitSwitchBot:
+			if err := a.abandon(); err != nil {
+				log.Printf("app waitRoom, a.abandon(); %v", err)
+			}
+			g.WPBot = true
+			g.TargetNick = ""
+			started, ok := a.restartGame(ctx, c, g, uiEvents)
+			if !ok {
+				return g, false
+			}
+			g = started
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"main/client"
+	"main/game"
+)
+
+const (
+	statusGameInProgress = "game_in_progress"
+	statusEnded          = "ended"
+
+	lastWin  = "win"
+	lastLose = "lose"
+
+	localToken  = "local"
+	defaultNick = "Player"
+)
+
+// LocalOptions configure a LocalGame. Zero values fall back to defaults.
+type LocalOptions struct {
+	Difficulty  Difficulty
+	Seed        int64
+	TurnTimeout time.Duration
+	// Delay is how long the bot "thinks" before each of its shots, so the
+	// battle screen can show them one by one.
+	Delay time.Duration
+}
+
+// LocalGame is a GameAPI served entirely in memory: the player fights a
+// bot shooter without any server. The bot fires lazily, one shot per
+// Delay, whenever the game state is read.
+type LocalGame struct {
+	mu sync.Mutex
+
+	opts    LocalOptions
+	rng     *rand.Rand
+	shooter Shooter
+	tracker *Tracker
+	token   string
+
+	nick, desc string
+	board      *game.Board
+	coords     []game.Coord
+	botBoard   *game.Board
+	botShots   []string
+
+	started    bool
+	playerTurn bool
+	status     string
+	won        bool
+	deadline   time.Time
+	botNextAt  time.Time
+}
+
+var _ client.GameAPI = (*LocalGame)(nil)
+
+func NewLocalGame(opts LocalOptions) *LocalGame {
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.TurnTimeout == 0 {
+		opts.TurnTimeout = 60 * time.Second
+	}
+	if opts.Delay == 0 {
+		opts.Delay = 700 * time.Millisecond
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	return &LocalGame{
+		opts:    opts,
+		rng:     rng,
+		shooter: New(opts.Difficulty, rng),
+		tracker: NewTracker(),
+	}
+}
+
+// BotNick is the opponent name shown for the given difficulty.
+func BotNick(d Difficulty) string {
+	return fmt.Sprintf("Local_Bot_%s", d)
+}
+
+func localError(method, endpoint string, code int, msg string) error {
+	return &client.APIError{StatusCode: code, Method: method, Endpoint: endpoint, Message: msg}
+}
+
+func (l *LocalGame) InitGame(ctx context.Context, g client.Game) (client.Game, error) {
+	if err := ctx.Err(); err != nil {
+		return client.Game{}, fmt.Errorf("InitGame: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	coords, err := game.ParseCoords(g.Coords)
+	if err != nil {
+		return client.Game{}, fmt.Errorf("InitGame: %w", localError(http.MethodPost, "/game", http.StatusBadRequest, err.Error()))
+	}
+	if len(coords) == 0 {
+		coords = game.RandomFleet(l.rng)
+	} else if vs := game.ValidateLayout(coords); len(vs) > 0 {
+		return client.Game{}, fmt.Errorf("InitGame: %w", localError(http.MethodPost, "/game", http.StatusBadRequest, vs.Error()))
+	}
+	l.nick = g.Nick
+	if l.nick == "" {
+		l.nick = defaultNick
+	}
+	l.desc = g.Desc
+	l.coords = coords
+	l.board = game.NewBoard(coords)
+	l.botBoard = game.NewBoard(game.RandomFleet(l.rng))
+	l.token = fmt.Sprintf("%s-%d", localToken, l.opts.Seed)
+	l.started = true
+	l.status = statusGameInProgress
+	l.playerTurn = l.rng.Intn(2) == 0
+	l.resetTurn(time.Now())
+	g.Nick = l.nick
+	return g, nil
+}
+
+func (l *LocalGame) resetTurn(now time.Time) {
+	l.deadline = now.Add(l.opts.TurnTimeout)
+	l.botNextAt = now.Add(l.opts.Delay)
+}
+
+// advance lets the bot take the shots that are due and ends the game
+// when the player ran out of time.
+func (l *LocalGame) advance(now time.Time) {
+	for l.status == statusGameInProgress {
+		if l.playerTurn {
+			if now.After(l.deadline) {
+				l.end(false)
+			}
+			return
+		}
+		if now.Before(l.botNextAt) {
+			return
+		}
+		target := l.shooter.Shoot(l.tracker)
+		result, err := l.board.Fire(target)
+		if err != nil {
+			// shooters only pick cells not shot yet, a repeat passes the turn
+			l.playerTurn = true
+			l.resetTurn(now)
+			return
+		}
+		l.tracker.Record(target, result)
+		l.botShots = append(l.botShots, target.String())
+		if l.board.Defeated() {
+			l.end(false)
+			return
+		}
+		if result == game.ResultMiss {
+			l.playerTurn = true
+			l.resetTurn(now)
+			return
+		}
+		l.botNextAt = l.botNextAt.Add(l.opts.Delay)
+	}
+}
+
+func (l *LocalGame) end(won bool) {
+	l.status = statusEnded
+	l.won = won
+}
+
+func (l *LocalGame) check(ctx context.Context, method, httpMethod, endpoint string) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if !l.started {
+		return fmt.Errorf("%s: %w", method, localError(httpMethod, endpoint, http.StatusNotFound, "no game in progress"))
+	}
+	return nil
+}
+
+func (l *LocalGame) GetStatus(ctx context.Context) (client.StatusResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.check(ctx, "GetStatus", http.MethodGet, "/game"); err != nil {
+		return client.StatusResponse{}, err
+	}
+	now := time.Now()
+	l.advance(now)
+	resp := client.StatusResponse{
+		GameStatus: l.status,
+		Nick:       l.nick,
+		Opponent:   BotNick(l.opts.Difficulty),
+		OppShots:   append([]string{}, l.botShots...),
+	}
+	switch l.status {
+	case statusGameInProgress:
+		resp.ShouldFire = l.playerTurn
+		if left := l.deadline.Sub(now); l.playerTurn && left > 0 {
+			resp.Timer = int(left.Round(time.Second) / time.Second)
+		}
+	case statusEnded:
+		resp.LastGameStatus = lastLose
+		if l.won {
+			resp.LastGameStatus = lastWin
+		}
+	}
+	return resp, nil
+}
+
+func (l *LocalGame) GetBoard(ctx context.Context) (client.Board, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.check(ctx, "GetBoard", http.MethodGet, "/game/board"); err != nil {
+		return client.Board{}, err
+	}
+	return client.Board{Board: game.FormatCoords(l.coords)}, nil
+}
+
+func (l *LocalGame) Shoot(ctx context.Context, coord string) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.check(ctx, "Shoot", http.MethodPost, "/game/fire"); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	l.advance(now)
+	if l.status != statusGameInProgress {
+		return "", fmt.Errorf("Shoot: %w", localError(http.MethodPost, "/game/fire", http.StatusBadRequest, "game has ended"))
+	}
+	if !l.playerTurn {
+		return "", fmt.Errorf("Shoot: %w", localError(http.MethodPost, "/game/fire", http.StatusForbidden, "not your turn"))
+	}
+	target, err := game.ParseCoord(coord)
+	if err != nil {
+		return "", fmt.Errorf("Shoot: %w", localError(http.MethodPost, "/game/fire", http.StatusBadRequest, err.Error()))
+	}
+	result, err := l.botBoard.Fire(target)
+	if err != nil {
+		return "", fmt.Errorf("Shoot: %w", localError(http.MethodPost, "/game/fire", http.StatusBadRequest, err.Error()))
+	}
+	switch {
+	case l.botBoard.Defeated():
+		l.end(true)
+	case result == game.ResultMiss:
+		l.playerTurn = false
+		l.resetTurn(now)
+	default:
+		l.resetTurn(now)
+	}
+	return string(result), nil
+}
+
+func (l *LocalGame) GetDescription(ctx context.Context) (client.GameDesc, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.check(ctx, "GetDescription", http.MethodGet, "/game/desc"); err != nil {
+		return client.GameDesc{}, err
+	}
+	return client.GameDesc{
+		Desc:     l.desc,
+		Nick:     l.nick,
+		OppDesc:  fmt.Sprintf("Offline bot, %s", l.opts.Difficulty.Description()),
+		Opponent: BotNick(l.opts.Difficulty),
+	}, nil
+}
+
+// GetPlayers returns an empty lobby, nobody else plays offline.
+func (l *LocalGame) GetPlayers(ctx context.Context) (client.PlayersStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("GetPlayers: %w", err)
+	}
+	return client.PlayersStatus{}, nil
+}
+
+func (l *LocalGame) Abandon(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.check(ctx, "Abandon", http.MethodDelete, "/game/abandon"); err != nil {
+		return err
+	}
+	if l.status == statusGameInProgress {
+		l.end(false)
+	}
+	return nil
+}
+
+// Refresh does nothing, the bot never leaves.
+func (l *LocalGame) Refresh(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.check(ctx, "Refresh", http.MethodGet, "/game/refresh")
+}
+
+// GetStats returns no rows, offline games are not ranked.
+func (l *LocalGame) GetStats(ctx context.Context) (client.StatsList, error) {
+	if err := ctx.Err(); err != nil {
+		return client.StatsList{}, fmt.Errorf("GetStats: %w", err)
+	}
+	return client.StatsList{}, nil
+}
+
+// GetPlayerStats finds nobody, offline games are not ranked.
+func (l *LocalGame) GetPlayerStats(ctx context.Context, nick string) (client.Stats, error) {
+	if err := ctx.Err(); err != nil {
+		return client.Stats{}, fmt.Errorf("GetPlayerStats: %w", err)
+	}
+	return client.Stats{}, fmt.Errorf("GetPlayerStats: %w", localError(http.MethodGet, "/stats/"+nick, http.StatusNotFound, "no stats offline"))
+}
+
+func (l *LocalGame) GetToken() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.token
+}
+
+func (l *LocalGame) SetToken(token string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.token = token
+}
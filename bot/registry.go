@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// Factory builds a shooter using rng for every random choice.
+type Factory func(rng *rand.Rand) Shooter
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{
+		"random": func(rng *rand.Rand) Shooter { return NewRandom(rng) },
+		"hunt-target": func(rng *rand.Rand) Shooter {
+			return NewHuntTarget(rng, false)
+		},
+		"hunt-target-parity": func(rng *rand.Rand) Shooter {
+			return NewHuntTarget(rng, true)
+		},
+		"density": func(rng *rand.Rand) Shooter { return NewDensity(rng) },
+	}
+)
+
+// Register makes a strategy available by name, e.g. to the tournament
+// runner. Registering a name twice replaces the earlier factory.
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = f
+}
+
+// Lookup returns the factory registered under name.
+func Lookup(name string) (Factory, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown strategy %q", name)
+	}
+	return f, nil
+}
+
+// Strategies returns the registered strategy names in sorted order.
+func Strategies() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
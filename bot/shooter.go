@@ -0,0 +1,234 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"main/game"
+)
+
+// Shooter picks the next cell to fire at from what it knows so far.
+type Shooter interface {
+	Name() string
+	Shoot(t *Tracker) game.Coord
+}
+
+// Difficulty selects one of the built-in shooters.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+)
+
+var Difficulties = []Difficulty{Easy, Medium, Hard}
+
+func (d Difficulty) String() string {
+	switch d {
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	default:
+		return "easy"
+	}
+}
+
+// Description explains how a difficulty plays.
+func (d Difficulty) Description() string {
+	switch d {
+	case Medium:
+		return "hunt and target with parity"
+	case Hard:
+		return "probability density targeting"
+	default:
+		return "random shooting"
+	}
+}
+
+// ParseDifficulty accepts the names returned by Difficulty.String.
+func ParseDifficulty(s string) (Difficulty, error) {
+	for _, d := range Difficulties {
+		if strings.EqualFold(s, d.String()) {
+			return d, nil
+		}
+	}
+	return Easy, fmt.Errorf("unknown difficulty %q", s)
+}
+
+// New returns the shooter playing at difficulty d.
+func New(d Difficulty, rng *rand.Rand) Shooter {
+	switch d {
+	case Medium:
+		return &HuntTarget{rng: rng, Parity: true}
+	case Hard:
+		return &Density{rng: rng}
+	default:
+		return &Random{rng: rng}
+	}
+}
+
+// Random fires at any cell not tried yet.
+type Random struct {
+	rng *rand.Rand
+}
+
+func NewRandom(rng *rand.Rand) *Random {
+	return &Random{rng: rng}
+}
+
+func (r *Random) Name() string {
+	return "random"
+}
+
+func (r *Random) Shoot(t *Tracker) game.Coord {
+	free := t.Available()
+	return free[r.rng.Intn(len(free))]
+}
+
+// HuntTarget hunts at random, optionally only on a checkerboard sized to
+// the smallest ship left, and once it hits targets the cells around the
+// hit until the ship sinks.
+type HuntTarget struct {
+	rng    *rand.Rand
+	Parity bool
+}
+
+func NewHuntTarget(rng *rand.Rand, parity bool) *HuntTarget {
+	return &HuntTarget{rng: rng, Parity: parity}
+}
+
+func (h *HuntTarget) Name() string {
+	if h.Parity {
+		return "hunt-target-parity"
+	}
+	return "hunt-target"
+}
+
+func (h *HuntTarget) Shoot(t *Tracker) game.Coord {
+	if targets := targetCells(t); len(targets) > 0 {
+		return targets[h.rng.Intn(len(targets))]
+	}
+	free := t.Available()
+	if h.Parity {
+		step := t.smallestRemaining()
+		if step < 2 {
+			step = 2
+		}
+		var even []game.Coord
+		for _, c := range free {
+			if (c.X+c.Y)%step == 0 {
+				even = append(even, c)
+			}
+		}
+		if len(even) > 0 {
+			free = even
+		}
+	}
+	return free[h.rng.Intn(len(free))]
+}
+
+// targetCells returns the cells worth trying around open hits. With two
+// or more hits in a line only the line ends are returned.
+func targetCells(t *Tracker) []game.Coord {
+	open := t.OpenHits()
+	if len(open) == 0 {
+		return nil
+	}
+	var res []game.Coord
+	seen := make(map[game.Coord]bool)
+	for _, h := range open {
+		ship := t.Board.ShipAt(h)
+		horizontal, vertical := false, false
+		for _, s := range ship {
+			if s.Y == h.Y && s.X != h.X {
+				horizontal = true
+			}
+			if s.X == h.X && s.Y != h.Y {
+				vertical = true
+			}
+		}
+		for _, n := range h.Orthogonal() {
+			if t.Board.At(n) != game.CellEmpty || seen[n] {
+				continue
+			}
+			if horizontal && n.Y != h.Y || vertical && n.X != h.X {
+				continue
+			}
+			seen[n] = true
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// Density fires at the cell covered by the most possible placements of
+// the ships left. Placements through open hits weigh much more, cells
+// around sunk ships are already excluded by the tracker.
+type Density struct {
+	rng *rand.Rand
+}
+
+func NewDensity(rng *rand.Rand) *Density {
+	return &Density{rng: rng}
+}
+
+func (d *Density) Name() string {
+	return "density"
+}
+
+const hitWeight = 20
+
+func (d *Density) Shoot(t *Tracker) game.Coord {
+	var density [game.Size][game.Size]int
+	for _, size := range t.Remaining {
+		for _, start := range game.AllCoords() {
+			for _, horizontal := range []bool{true, false} {
+				if size == 1 && !horizontal {
+					continue
+				}
+				ship := game.Line(start, size, horizontal)
+				weight, ok := placementWeight(t, ship)
+				if !ok {
+					continue
+				}
+				for _, c := range ship {
+					if t.Board.At(c) == game.CellEmpty {
+						density[c.X][c.Y] += weight
+					}
+				}
+			}
+		}
+	}
+	var best []game.Coord
+	bestScore := -1
+	for _, c := range t.Available() {
+		switch score := density[c.X][c.Y]; {
+		case score > bestScore:
+			best, bestScore = []game.Coord{c}, score
+		case score == bestScore:
+			best = append(best, c)
+		}
+	}
+	return best[d.rng.Intn(len(best))]
+}
+
+// placementWeight tells whether a ship could lie on the given cells and
+// how strongly it is supported by open hits.
+func placementWeight(t *Tracker, ship []game.Coord) (int, bool) {
+	weight := 1
+	for _, c := range ship {
+		if !c.Valid() || t.Sunk[c] {
+			return 0, false
+		}
+		switch t.Board.At(c) {
+		case game.CellMiss:
+			return 0, false
+		case game.CellHit:
+			weight *= hitWeight
+		}
+	}
+	return weight, true
+}
@@ -0,0 +1,119 @@
+// Package bot contains computer shooters and a local game engine that
+// plays them against a human without a server.
+package bot
+
+import "main/game"
+
+// Tracker is everything a shooter knows about the opponent board: which
+// cells were hit or missed, which ships were sunk and which are left.
+type Tracker struct {
+	Board     *game.Board
+	Sunk      map[game.Coord]bool
+	Remaining []int
+	shots     int
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		Board:     &game.Board{},
+		Sunk:      make(map[game.Coord]bool),
+		Remaining: append([]int(nil), game.FleetSizes...),
+	}
+}
+
+// Record applies the result of a shot at c. A sunk ship is removed from
+// Remaining and the cells around it are marked as misses.
+func (t *Tracker) Record(c game.Coord, result game.Result) {
+	t.shots++
+	switch result {
+	case game.ResultMiss:
+		t.Board.Set(c, game.CellMiss)
+	case game.ResultHit:
+		t.Board.Set(c, game.CellHit)
+	case game.ResultSunk:
+		t.Board.Set(c, game.CellHit)
+		ship := t.Board.ShipAt(c)
+		for _, s := range ship {
+			t.Sunk[s] = true
+		}
+		t.removeSize(len(ship))
+		t.Board.MarkSunk(c)
+	}
+}
+
+func (t *Tracker) removeSize(size int) {
+	for i, s := range t.Remaining {
+		if s == size {
+			t.Remaining = append(t.Remaining[:i], t.Remaining[i+1:]...)
+			return
+		}
+	}
+}
+
+// Available returns every cell not fired at yet.
+func (t *Tracker) Available() []game.Coord {
+	return t.Board.Cells(game.CellEmpty)
+}
+
+// OpenHits returns hit cells of ships that are not sunk yet.
+func (t *Tracker) OpenHits() []game.Coord {
+	var res []game.Coord
+	for _, c := range t.Board.Cells(game.CellHit) {
+		if !t.Sunk[c] {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Shots returns how many shots were recorded.
+func (t *Tracker) Shots() int {
+	return t.shots
+}
+
+func (t *Tracker) smallestRemaining() int {
+	min := 0
+	for _, s := range t.Remaining {
+		if min == 0 || s < min {
+			min = s
+		}
+	}
+	return min
+}
+
+// TrackerFromBoard rebuilds a tracker from a board of hits and misses. A
+// group of hits with no empty cell around it cannot grow any more, so it
+// is counted as a sunk ship.
+func TrackerFromBoard(b *game.Board) *Tracker {
+	t := NewTracker()
+	t.shots = len(b.Cells(game.CellHit, game.CellMiss))
+	for _, c := range game.AllCoords() {
+		switch b.At(c) {
+		case game.CellHit:
+			t.Board.Set(c, game.CellHit)
+		case game.CellMiss:
+			t.Board.Set(c, game.CellMiss)
+		}
+	}
+	for _, c := range t.Board.Cells(game.CellHit) {
+		if t.Sunk[c] {
+			continue
+		}
+		ship := t.Board.ShipAt(c)
+		closed := true
+		for _, s := range ship {
+			for _, n := range s.Orthogonal() {
+				if t.Board.At(n) == game.CellEmpty {
+					closed = false
+				}
+			}
+		}
+		if closed {
+			for _, s := range ship {
+				t.Sunk[s] = true
+			}
+			t.removeSize(len(ship))
+		}
+	}
+	return t
+}
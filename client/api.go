@@ -0,0 +1,33 @@
+package client
+
+import "context"
+
+// GameAPI is the set of game server operations used by the app.
+// Client implements it against the HTTP server, FakeClient keeps
+// everything in memory. Every call gives up as soon as ctx is done.
+type GameAPI interface {
+	InitGame(ctx context.Context, game Game) (Game, error)
+	GetStatus(ctx context.Context) (StatusResponse, error)
+	GetBoard(ctx context.Context) (Board, error)
+	Shoot(ctx context.Context, coord string) (string, error)
+	GetDescription(ctx context.Context) (GameDesc, error)
+	GetPlayers(ctx context.Context) (PlayersStatus, error)
+	Abandon(ctx context.Context) error
+	Refresh(ctx context.Context) error
+	GetStats(ctx context.Context) (StatsList, error)
+	GetPlayerStats(ctx context.Context, nick string) (Stats, error)
+	GetToken() string
+	SetToken(token string)
+}
+
+var _ GameAPI = (*Client)(nil)
+
+// SetToken restores the token of a game started earlier, e.g. a resumed session.
+func (c *Client) SetToken(token string) {
+	c.Token = token
+}
+
+// GetToken returns the X-Auth-Token of the current game, empty if no game was started.
+func (c *Client) GetToken() string {
+	return c.Token
+}
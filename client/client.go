@@ -15,42 +15,76 @@ const (
 )
 
 const (
-	clientTimeout    = time.Second * 30
-	contentType      = "application/json"
-	initGameDelay    = 1 * time.Second
-	boardDelay       = time.Millisecond * 300
-	statusDelay      = time.Millisecond * 300
-	fireDelay        = time.Millisecond * 300
-	descriptionDelay = time.Millisecond * 300
-	playersDelay     = time.Millisecond * 300
-	abandonDelay     = time.Millisecond * 300
-	requestDelay     = time.Millisecond * 500
-	maxRequests      = 10
+	clientTimeout   = time.Second * 30
+	contentType     = "application/json"
+	requestDelay    = time.Millisecond * 500
+	maxRetryDelay   = 5 * time.Second
+	maxRetryElapsed = 30 * time.Second
+	maxRequests     = 10
 )
 
 type Client struct {
 	client  *http.Client
 	baseURL string
-	Token   string
+	timeout time.Duration
+	// timeoutSet is true when WithTimeout was given, which then overrides
+	// the timeout of a client passed to WithHTTPClient.
+	timeoutSet bool
+	retry      RetryPolicy
+	limiter    *RateLimiter
+	Token      string
 }
 
-func NewClient() *Client {
-	return &Client{
-		client: &http.Client{
-			Timeout: clientTimeout,
-		},
+// idempotent tells which endpoints may be retried after the server answered.
+var idempotent = map[string]bool{
+	"InitGame":       false,
+	"GetBoard":       true,
+	"GetStatus":      true,
+	"Shoot":          false,
+	"GetDescription": true,
+	"GetPlayers":     true,
+	"Abandon":        true,
+	"GetStats":       true,
+	"GetPlayerStats": true,
+	"Refresh":        true,
+}
+
+// DefaultBaseURL is the public server used when no other URL is configured.
+const DefaultBaseURL = httpAPIURLAddress
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
 		baseURL: httpAPIURLAddress,
+		timeout: clientTimeout,
+		retry:   DefaultRetryPolicy(),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	if c.limiter == nil {
+		c.limiter = NewRateLimiter(DefaultRateLimit(), DefaultEndpointLimits())
+	}
+	if c.client == nil {
+		c.client = &http.Client{
+			Timeout: c.timeout,
+		}
+	} else if c.timeoutSet {
+		// copy so a shared client such as http.DefaultClient keeps its timeout
+		hc := *c.client
+		hc.Timeout = c.timeout
+		c.client = &hc
+	}
+	return c
 }
 
-func (c *Client) InitGame(game Game) (Game, error) {
+func (c *Client) InitGame(ctx context.Context, game Game) (Game, error) {
 	requestFunc := func() (interface{}, error) {
 		urlPath := c.buildURL("/game")
 		gameJSON, err := json.Marshal(game)
 		if err != nil {
 			return Game{}, fmt.Errorf("InitGame: json.Marshal: %v", err)
 		}
-		req, err := c.newRequest(http.MethodPost, urlPath, bytes.NewReader(gameJSON))
+		req, err := c.newRequest(ctx, http.MethodPost, urlPath, bytes.NewReader(gameJSON))
 		if err != nil {
 			return Game{}, fmt.Errorf("InitGame: sendRequest: %w", err)
 		}
@@ -60,16 +94,15 @@ func (c *Client) InitGame(game Game) (Game, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return Game{}, fmt.Errorf("InitGame: unexpected response status: %s", resp.Status)
+			return Game{}, newAPIError(req, resp)
 		}
 		c.Token = resp.Header.Get("X-Auth-Token")
-		time.Sleep(initGameDelay)
 		return game, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "InitGame", requestFunc)
 	if err != nil {
-		return Game{}, fmt.Errorf("InitGame: %w", err)
+		return Game{}, err
 	}
 
 	result, ok := resp.(Game)
@@ -79,14 +112,14 @@ func (c *Client) InitGame(game Game) (Game, error) {
 	return result, nil
 }
 
-func (c *Client) GetBoard() (Board, error) {
+func (c *Client) GetBoard(ctx context.Context) (Board, error) {
 	requestFunc := func() (interface{}, error) {
 		if c.Token == "" {
-			return Board{}, fmt.Errorf("GetBoard: no token")
+			return Board{}, fmt.Errorf("GetBoard: %w", ErrNoToken)
 		}
 		urlPath := c.buildURL("/game/board")
 		reqBody := bytes.NewReader([]byte{})
-		req, err := c.newRequest(http.MethodGet, urlPath, reqBody)
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
 		if err != nil {
 			return Board{}, fmt.Errorf("GetBoard: sendRequest: %w", err)
 		}
@@ -96,20 +129,19 @@ func (c *Client) GetBoard() (Board, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return Board{}, fmt.Errorf("GetBoard: unexpected response status: %s", resp.Status)
+			return Board{}, newAPIError(req, resp)
 		}
 		var board Board
 		err = json.NewDecoder(resp.Body).Decode(&board)
 		if err != nil {
-			return Board{}, fmt.Errorf("GetBoard: error decoding response body: %w", err)
+			return Board{}, &respondedError{fmt.Errorf("GetBoard: error decoding response body: %w", err)}
 		}
-		time.Sleep(boardDelay)
 		return board, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "GetBoard", requestFunc)
 	if err != nil {
-		return Board{}, fmt.Errorf("GetBoard: %w", err)
+		return Board{}, err
 	}
 
 	result, ok := resp.(Board)
@@ -119,14 +151,14 @@ func (c *Client) GetBoard() (Board, error) {
 	return result, nil
 }
 
-func (c *Client) GetStatus() (StatusResponse, error) {
+func (c *Client) GetStatus(ctx context.Context) (StatusResponse, error) {
 	requestFunc := func() (interface{}, error) {
 		if c.Token == "" {
-			return StatusResponse{}, fmt.Errorf("GetStatus: no token")
+			return StatusResponse{}, fmt.Errorf("GetStatus: %w", ErrNoToken)
 		}
 		urlPath := c.buildURL("/game")
 		reqBody := bytes.NewReader([]byte{})
-		req, err := c.newRequest(http.MethodGet, urlPath, reqBody)
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
 		if err != nil {
 			return StatusResponse{}, fmt.Errorf("GetStatus: sendRequest: %w", err)
 		}
@@ -136,20 +168,19 @@ func (c *Client) GetStatus() (StatusResponse, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return StatusResponse{}, fmt.Errorf("GetStatus: unexpected response status: %s", resp.Status)
+			return StatusResponse{}, newAPIError(req, resp)
 		}
 		var status StatusResponse
 		err = json.NewDecoder(resp.Body).Decode(&status)
 		if err != nil {
-			return StatusResponse{}, fmt.Errorf("GetStatus: error decoding response body: %w", err)
+			return StatusResponse{}, &respondedError{fmt.Errorf("GetStatus: error decoding response body: %w", err)}
 		}
-		time.Sleep(statusDelay)
 		return status, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "GetStatus", requestFunc)
 	if err != nil {
-		return StatusResponse{}, fmt.Errorf("GetStatus: %w", err)
+		return StatusResponse{}, err
 	}
 
 	result, ok := resp.(StatusResponse)
@@ -159,10 +190,10 @@ func (c *Client) GetStatus() (StatusResponse, error) {
 	return result, nil
 }
 
-func (c *Client) Shoot(coord string) (string, error) {
+func (c *Client) Shoot(ctx context.Context, coord string) (string, error) {
 	requestFunc := func() (interface{}, error) {
 		if c.Token == "" {
-			return "", fmt.Errorf("Shoot: no token")
+			return "", fmt.Errorf("Shoot: %w", ErrNoToken)
 		}
 		urlPath := c.buildURL("/game/fire")
 		shot := Shot{Coord: coord}
@@ -170,7 +201,7 @@ func (c *Client) Shoot(coord string) (string, error) {
 		if err != nil {
 			return "", fmt.Errorf("Shoot: json.Marshal: %w", err)
 		}
-		req, err := c.newRequest(http.MethodPost, urlPath, bytes.NewReader(shotJSON))
+		req, err := c.newRequest(ctx, http.MethodPost, urlPath, bytes.NewReader(shotJSON))
 		if err != nil {
 			return "", fmt.Errorf("Shoot: sendRequest: %w", err)
 		}
@@ -180,20 +211,19 @@ func (c *Client) Shoot(coord string) (string, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return "", fmt.Errorf("Shoot: unexpected response status: %s", resp.Status)
+			return "", newAPIError(req, resp)
 		}
 		var result ShotResult
 		err = json.NewDecoder(resp.Body).Decode(&result)
 		if err != nil {
-			return "", fmt.Errorf("Shoot: error decoding response body: %w", err)
+			return "", &respondedError{fmt.Errorf("Shoot: error decoding response body: %w", err)}
 		}
-		time.Sleep(fireDelay)
 		return result.Result, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "Shoot", requestFunc)
 	if err != nil {
-		return "", fmt.Errorf("Shoot: %w", err)
+		return "", err
 	}
 
 	result, ok := resp.(string)
@@ -203,14 +233,14 @@ func (c *Client) Shoot(coord string) (string, error) {
 	return result, nil
 }
 
-func (c *Client) GetDescription() (GameDesc, error) {
+func (c *Client) GetDescription(ctx context.Context) (GameDesc, error) {
 	requestFunc := func() (interface{}, error) {
 		if c.Token == "" {
-			return GameDesc{}, fmt.Errorf("GetDescription: no token")
+			return GameDesc{}, fmt.Errorf("GetDescription: %w", ErrNoToken)
 		}
 		urlPath := c.buildURL("/game/desc")
 		reqBody := bytes.NewReader([]byte{})
-		req, err := c.newRequest(http.MethodGet, urlPath, reqBody)
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
 		if err != nil {
 			return GameDesc{}, fmt.Errorf("GetDescription: sendRequest: %w", err)
 		}
@@ -220,20 +250,19 @@ func (c *Client) GetDescription() (GameDesc, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return GameDesc{}, fmt.Errorf("GetDescription: unexpected response status: %s", resp.Status)
+			return GameDesc{}, newAPIError(req, resp)
 		}
 		var desc GameDesc
 		err = json.NewDecoder(resp.Body).Decode(&desc)
 		if err != nil {
-			return GameDesc{}, fmt.Errorf("GetDescription: error decoding response body: %w", err)
+			return GameDesc{}, &respondedError{fmt.Errorf("GetDescription: error decoding response body: %w", err)}
 		}
-		time.Sleep(descriptionDelay)
 		return desc, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "GetDescription", requestFunc)
 	if err != nil {
-		return GameDesc{}, fmt.Errorf("GetDescription: %w", err)
+		return GameDesc{}, err
 	}
 
 	result, ok := resp.(GameDesc)
@@ -243,11 +272,11 @@ func (c *Client) GetDescription() (GameDesc, error) {
 	return result, nil
 }
 
-func (c *Client) GetPlayers() (PlayersStatus, error) {
+func (c *Client) GetPlayers(ctx context.Context) (PlayersStatus, error) {
 	requestFunc := func() (interface{}, error) {
 		urlPath := c.buildURL("/lobby")
 		reqBody := bytes.NewReader([]byte{})
-		req, err := c.newRequest(http.MethodGet, urlPath, reqBody)
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
 		if err != nil {
 			return PlayersStatus{}, fmt.Errorf("GetPlayers: sendRequest: %w", err)
 		}
@@ -257,20 +286,19 @@ func (c *Client) GetPlayers() (PlayersStatus, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return PlayersStatus{}, fmt.Errorf("GetPlayers: unexpected response status: %s", resp.Status)
+			return PlayersStatus{}, newAPIError(req, resp)
 		}
 		var players PlayersStatus
 		err = json.NewDecoder(resp.Body).Decode(&players)
 		if err != nil {
-			return PlayersStatus{}, fmt.Errorf("GetPlayers: error decoding response body: %w", err)
+			return PlayersStatus{}, &respondedError{fmt.Errorf("GetPlayers: error decoding response body: %w", err)}
 		}
-		time.Sleep(playersDelay)
 		return players, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "GetPlayers", requestFunc)
 	if err != nil {
-		return PlayersStatus{}, fmt.Errorf("GetPlayers: %w", err)
+		return PlayersStatus{}, err
 	}
 
 	players, ok := resp.(PlayersStatus)
@@ -280,11 +308,11 @@ func (c *Client) GetPlayers() (PlayersStatus, error) {
 	return players, nil
 }
 
-func (c *Client) Abandon() error {
+func (c *Client) Abandon(ctx context.Context) error {
 	requestFunc := func() (interface{}, error) {
 		urlPath := c.buildURL("/game/abandon")
 		reqBody := bytes.NewReader([]byte{})
-		req, err := c.newRequest(http.MethodDelete, urlPath, reqBody)
+		req, err := c.newRequest(ctx, http.MethodDelete, urlPath, reqBody)
 		if err != nil {
 			return nil, fmt.Errorf("Abandon: sendRequest: %w", err)
 		}
@@ -294,24 +322,23 @@ func (c *Client) Abandon() error {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("Abandon: unexpected response status: %s", resp.Status)
+			return nil, newAPIError(req, resp)
 		}
-		time.Sleep(abandonDelay)
 		return nil, nil
 	}
 
-	_, err := c.doRequest(requestFunc)
+	_, err := c.doRequest(ctx, "Abandon", requestFunc)
 	if err != nil {
-		return fmt.Errorf("Abandon: %w", err)
+		return err
 	}
 	return nil
 }
 
-func (c *Client) GetStats() (StatsList, error) {
+func (c *Client) GetStats(ctx context.Context) (StatsList, error) {
 	requestFunc := func() (interface{}, error) {
 		urlPath := c.buildURL("/stats")
 		reqBody := bytes.NewReader([]byte{})
-		req, err := c.newRequest(http.MethodGet, urlPath, reqBody)
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
 		if err != nil {
 			return StatsList{}, fmt.Errorf("GetStats: sendRequest: %w", err)
 		}
@@ -321,20 +348,19 @@ func (c *Client) GetStats() (StatsList, error) {
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return StatsList{}, fmt.Errorf("GetStats: unexpected response status: %s", resp.Status)
+			return StatsList{}, newAPIError(req, resp)
 		}
 		var stats StatsList
 		err = json.NewDecoder(resp.Body).Decode(&stats)
 		if err != nil {
-			return StatsList{}, fmt.Errorf("GetStats: error decoding response body: %w", err)
+			return StatsList{}, &respondedError{fmt.Errorf("GetStats: error decoding response body: %w", err)}
 		}
-		time.Sleep(statusDelay)
 		return stats, nil
 	}
 
-	resp, err := c.doRequest(requestFunc)
+	resp, err := c.doRequest(ctx, "GetStats", requestFunc)
 	if err != nil {
-		return StatsList{}, fmt.Errorf("GetStats: %w", err)
+		return StatsList{}, err
 	}
 
 	result, ok := resp.(StatsList)
@@ -344,13 +370,77 @@ func (c *Client) GetStats() (StatsList, error) {
 	return result, nil
 }
 
+// Refresh keeps us registered in the lobby while waiting for an opponent.
+func (c *Client) Refresh(ctx context.Context) error {
+	requestFunc := func() (interface{}, error) {
+		if c.Token == "" {
+			return nil, fmt.Errorf("Refresh: %w", ErrNoToken)
+		}
+		urlPath := c.buildURL("/game/refresh")
+		reqBody := bytes.NewReader([]byte{})
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("Refresh: sendRequest: %w", err)
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("Refresh: client.Do(req): %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, newAPIError(req, resp)
+		}
+		return nil, nil
+	}
+
+	_, err := c.doRequest(ctx, "Refresh", requestFunc)
+	return err
+}
+
+// GetPlayerStats returns the stats of a single player.
+func (c *Client) GetPlayerStats(ctx context.Context, nick string) (Stats, error) {
+	requestFunc := func() (interface{}, error) {
+		urlPath := c.buildURL("/stats/" + url.PathEscape(nick))
+		reqBody := bytes.NewReader([]byte{})
+		req, err := c.newRequest(ctx, http.MethodGet, urlPath, reqBody)
+		if err != nil {
+			return Stats{}, fmt.Errorf("GetPlayerStats: sendRequest: %w", err)
+		}
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return Stats{}, fmt.Errorf("GetPlayerStats: client.Do(req): %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return Stats{}, newAPIError(req, resp)
+		}
+		var stats PlayerStats
+		err = json.NewDecoder(resp.Body).Decode(&stats)
+		if err != nil {
+			return Stats{}, &respondedError{fmt.Errorf("GetPlayerStats: error decoding response body: %w", err)}
+		}
+		return stats.Stats, nil
+	}
+
+	resp, err := c.doRequest(ctx, "GetPlayerStats", requestFunc)
+	if err != nil {
+		return Stats{}, err
+	}
+
+	result, ok := resp.(Stats)
+	if !ok {
+		return Stats{}, fmt.Errorf("GetPlayerStats: unexpected response type")
+	}
+	return result, nil
+}
+
 func (c *Client) buildURL(endpoint string) string {
 	baseURL, _ := url.Parse(c.baseURL)
 	return baseURL.JoinPath(endpoint).String()
 }
 
-func (c *Client) newRequest(method, url string, body *bytes.Reader) (*http.Request, error) {
-	req, err := http.NewRequestWithContext(context.Background(), method, url, body)
+func (c *Client) newRequest(ctx context.Context, method, url string, body *bytes.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("newRequest: http.NewRequestWithContext: %w", err)
 	}
@@ -360,15 +450,51 @@ func (c *Client) newRequest(method, url string, body *bytes.Reader) (*http.Reque
 	req.Header.Set("Content-Type", contentType)
 	return req, nil
 }
-func (c *Client) doRequest(requestFunc func() (interface{}, error)) (interface{}, error) {
-	var error error
-	for i := 0; i < maxRequests; i++ {
+
+// doRequest runs requestFunc until it succeeds or the retry policy gives up.
+func (c *Client) doRequest(ctx context.Context, endpoint string, requestFunc func() (interface{}, error)) (interface{}, error) {
+	start := time.Now()
+	attempt := Attempt{Endpoint: endpoint, Idempotent: idempotent[endpoint]}
+	for {
+		attempt.Number++
+		if err := ctx.Err(); err != nil {
+			return nil, &RetryError{Endpoint: endpoint, Attempts: attempt.Number - 1, Elapsed: time.Since(start), Err: err}
+		}
+		if err := c.limiter.Wait(ctx, endpoint); err != nil {
+			return nil, &RetryError{Endpoint: endpoint, Attempts: attempt.Number - 1, Elapsed: time.Since(start), Err: err}
+		}
 		resp, err := requestFunc()
 		if err == nil {
 			return resp, nil
 		}
-		error = err
-		time.Sleep(requestDelay)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		attempt.Err = err
+		attempt.Elapsed = time.Since(start)
+		attempt.Responded = responded(err)
+		attempt.RetryAfter = retryAfter(err)
+		delay, ok := c.retry.Next(attempt)
+		if !ok {
+			return nil, &RetryError{Endpoint: endpoint, Attempts: attempt.Number, Elapsed: attempt.Elapsed, Err: err}
+		}
+		if !c.sleep(ctx, delay) {
+			return nil, &RetryError{Endpoint: endpoint, Attempts: attempt.Number, Elapsed: time.Since(start), Err: ctx.Err()}
+		}
+	}
+}
+
+// sleep waits for d or until ctx is done, reporting whether the full delay elapsed.
+func (c *Client) sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
 	}
-	return nil, fmt.Errorf("request failed after %d retries; %v", maxRequests, error)
 }
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const maxErrorBody = 4096
+
+// Sentinel errors matched by APIError, use them with errors.Is.
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotYourTurn  = errors.New("not your turn")
+	ErrGameNotFound = errors.New("game not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrNoToken      = errors.New("no token")
+
+	ErrPlayerNotFound = errors.New("player not found")
+)
+
+// APIError is returned for every non-200 response from the server.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Endpoint   string
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is maps the status code to one of the sentinel errors.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrNotYourTurn:
+		return e.StatusCode == http.StatusForbidden
+	case ErrGameNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrPlayerNotFound:
+		return e.StatusCode == http.StatusNotFound && strings.Contains(e.Endpoint, "/stats/")
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed response, decoding the
+// server message from a {"message": ...} or {"error": ...} body and
+// falling back to the raw body text.
+func newAPIError(req *http.Request, resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Endpoint:   req.URL.Path,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return apiErr
+	}
+	var decoded struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &decoded) == nil && (decoded.Message != "" || decoded.Error != "") {
+		apiErr.Message = decoded.Message
+		if apiErr.Message == "" {
+			apiErr.Message = decoded.Error
+		}
+		return apiErr
+	}
+	apiErr.Message = strings.TrimSpace(string(body))
+	return apiErr
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		status   int
+		endpoint string
+		target   error
+		want     bool
+	}{
+		{status: http.StatusUnauthorized, target: ErrUnauthorized, want: true},
+		{status: http.StatusForbidden, target: ErrNotYourTurn, want: true},
+		{status: http.StatusNotFound, target: ErrGameNotFound, want: true},
+		{status: http.StatusTooManyRequests, target: ErrRateLimited, want: true},
+		{status: http.StatusNotFound, endpoint: "/api/stats/bob", target: ErrPlayerNotFound, want: true},
+		{status: http.StatusNotFound, endpoint: "/api/game", target: ErrPlayerNotFound, want: false},
+		{status: http.StatusInternalServerError, target: ErrGameNotFound, want: false},
+		{status: http.StatusBadRequest, target: ErrNoToken, want: false},
+	}
+	for _, tt := range tests {
+		err := fmt.Errorf("Shoot: %w", &APIError{StatusCode: tt.status, Method: http.MethodGet, Endpoint: tt.endpoint})
+		if got := errors.Is(err, tt.target); got != tt.want {
+			t.Errorf("errors.Is(%d %s, %v) = %t, want %t", tt.status, tt.endpoint, tt.target, got, tt.want)
+		}
+	}
+}
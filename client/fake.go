@@ -0,0 +1,235 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+const fakeToken = "fake-token"
+
+// FakeClient is an in-memory GameAPI used to drive the app without a server.
+// GetStatus walks through Statuses one call at a time and keeps returning the
+// last entry once the script is exhausted, Shoot pops ShotResults in order and
+// falls back to "miss". Errs makes the named method ("GetStatus", "Shoot"...)
+// fail with the given error. All calls are recorded and safe for concurrent use.
+type FakeClient struct {
+	mu sync.Mutex
+
+	Token       string
+	Statuses    []StatusResponse
+	ShotResults []string
+	Desc        GameDesc
+	BoardResp   Board
+	Players     PlayersStatus
+	Stats       StatsList
+	Errs        map[string]error
+
+	Games     []Game
+	Shots     []string
+	Abandoned bool
+	calls     map[string]int
+}
+
+var _ GameAPI = (*FakeClient)(nil)
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		Errs:  make(map[string]error),
+		calls: make(map[string]int),
+	}
+}
+
+// PushStatus appends status transitions to the GetStatus script.
+func (f *FakeClient) PushStatus(statuses ...StatusResponse) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Statuses = append(f.Statuses, statuses...)
+}
+
+// PushShotResults appends results returned by subsequent Shoot calls.
+func (f *FakeClient) PushShotResults(results ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ShotResults = append(f.ShotResults, results...)
+}
+
+// Calls returns how many times the named method was called.
+func (f *FakeClient) Calls(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[method]
+}
+
+// ShotsFired returns a copy of every coordinate passed to Shoot.
+func (f *FakeClient) ShotsFired() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.Shots...)
+}
+
+func (f *FakeClient) record(ctx context.Context, method string) error {
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[method]++
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	if err, ok := f.Errs[method]; ok && err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+	return nil
+}
+
+func (f *FakeClient) InitGame(ctx context.Context, game Game) (Game, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "InitGame"); err != nil {
+		return Game{}, err
+	}
+	f.Games = append(f.Games, game)
+	f.Token = fakeToken
+	if len(game.Coords) != 0 {
+		f.BoardResp = Board{Board: append([]string(nil), game.Coords...)}
+	}
+	if game.Nick != "" {
+		f.Desc.Nick = game.Nick
+	}
+	if game.Desc != "" {
+		f.Desc.Desc = game.Desc
+	}
+	if game.TargetNick != "" {
+		f.Desc.Opponent = game.TargetNick
+	}
+	return game, nil
+}
+
+func (f *FakeClient) GetStatus(ctx context.Context) (StatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "GetStatus"); err != nil {
+		return StatusResponse{}, err
+	}
+	if f.Token == "" {
+		return StatusResponse{}, fmt.Errorf("GetStatus: %w", ErrNoToken)
+	}
+	if len(f.Statuses) == 0 {
+		return StatusResponse{}, nil
+	}
+	status := f.Statuses[0]
+	if len(f.Statuses) > 1 {
+		f.Statuses = f.Statuses[1:]
+	}
+	return status, nil
+}
+
+func (f *FakeClient) GetBoard(ctx context.Context) (Board, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "GetBoard"); err != nil {
+		return Board{}, err
+	}
+	if f.Token == "" {
+		return Board{}, fmt.Errorf("GetBoard: %w", ErrNoToken)
+	}
+	return Board{Board: append([]string(nil), f.BoardResp.Board...)}, nil
+}
+
+func (f *FakeClient) Shoot(ctx context.Context, coord string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "Shoot"); err != nil {
+		return "", err
+	}
+	if f.Token == "" {
+		return "", fmt.Errorf("Shoot: %w", ErrNoToken)
+	}
+	f.Shots = append(f.Shots, coord)
+	if len(f.ShotResults) == 0 {
+		return "miss", nil
+	}
+	result := f.ShotResults[0]
+	f.ShotResults = f.ShotResults[1:]
+	return result, nil
+}
+
+func (f *FakeClient) GetDescription(ctx context.Context) (GameDesc, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "GetDescription"); err != nil {
+		return GameDesc{}, err
+	}
+	if f.Token == "" {
+		return GameDesc{}, fmt.Errorf("GetDescription: %w", ErrNoToken)
+	}
+	return f.Desc, nil
+}
+
+func (f *FakeClient) GetPlayers(ctx context.Context) (PlayersStatus, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "GetPlayers"); err != nil {
+		return nil, err
+	}
+	return f.Players, nil
+}
+
+func (f *FakeClient) Abandon(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "Abandon"); err != nil {
+		return err
+	}
+	f.Abandoned = true
+	return nil
+}
+
+func (f *FakeClient) GetStats(ctx context.Context) (StatsList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "GetStats"); err != nil {
+		return StatsList{}, err
+	}
+	return f.Stats, nil
+}
+
+func (f *FakeClient) Refresh(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "Refresh"); err != nil {
+		return err
+	}
+	if f.Token == "" {
+		return fmt.Errorf("Refresh: %w", ErrNoToken)
+	}
+	return nil
+}
+
+// GetPlayerStats looks the nick up in Stats.
+func (f *FakeClient) GetPlayerStats(ctx context.Context, nick string) (Stats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.record(ctx, "GetPlayerStats"); err != nil {
+		return Stats{}, err
+	}
+	for _, st := range f.Stats.Stats {
+		if st.Nick == nick {
+			return st, nil
+		}
+	}
+	return Stats{}, &APIError{StatusCode: http.StatusNotFound, Method: http.MethodGet, Endpoint: "/stats/" + nick}
+}
+
+func (f *FakeClient) GetToken() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Token
+}
+
+func (f *FakeClient) SetToken(token string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Token = token
+}
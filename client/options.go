@@ -0,0 +1,57 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(*Client)
+
+// WithBaseURL points the client at another server, e.g. http://localhost:8080/api.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient replaces the underlying http.Client.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.client = hc
+	}
+}
+
+// WithTimeout sets the timeout of a single HTTP request. A client passed
+// to WithHTTPClient is copied rather than changed.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+		c.timeoutSet = true
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy, nil disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		if policy == nil {
+			policy = &BackoffPolicy{MaxAttempts: 1}
+		}
+		c.retry = policy
+	}
+}
+
+// WithRateLimits replaces the global and per endpoint request budgets.
+// Endpoints are named after the Client methods, e.g. "GetStatus".
+func WithRateLimits(global Limit, endpoints map[string]Limit) Option {
+	return func(c *Client) {
+		c.limiter = NewRateLimiter(global, endpoints)
+	}
+}
+
+// WithRateLimiter shares an existing limiter, e.g. between consecutive games.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
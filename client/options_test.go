@@ -0,0 +1,37 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWithTimeoutCopiesHTTPClient(t *testing.T) {
+	shared := &http.Client{}
+	c := NewClient(WithHTTPClient(shared), WithTimeout(time.Second))
+	if shared.Timeout != 0 {
+		t.Errorf("shared client timeout = %v, want it untouched", shared.Timeout)
+	}
+	if c.client.Timeout != time.Second {
+		t.Errorf("client timeout = %v, want 1s", c.client.Timeout)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want time.Duration
+	}{
+		{name: "default", want: clientTimeout},
+		{name: "set", opts: []Option{WithTimeout(time.Second)}, want: time.Second},
+		{name: "http client keeps its own", opts: []Option{WithHTTPClient(&http.Client{Timeout: time.Minute})}, want: time.Minute},
+		{name: "default value given explicitly", opts: []Option{WithHTTPClient(&http.Client{Timeout: time.Minute}), WithTimeout(clientTimeout)}, want: clientTimeout},
+		{name: "option order does not matter", opts: []Option{WithTimeout(clientTimeout), WithHTTPClient(&http.Client{Timeout: time.Minute})}, want: clientTimeout},
+	}
+	for _, tt := range tests {
+		if got := NewClient(tt.opts...).client.Timeout; got != tt.want {
+			t.Errorf("%s: timeout = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package client
 
-type PlayersStatus []struct {
+type PlayerStatus struct {
 	GameStatus string `json:"game_status"`
 	Nick       string `json:"nick"`
 }
 
+type PlayersStatus []PlayerStatus
+
 type Game struct {
 	Coords     []string `json:"coords"`
 	Desc       string   `json:"desc"`
@@ -52,3 +54,7 @@ type Stats struct {
 type StatsList struct {
 	Stats []Stats `json:"stats"`
 }
+
+type PlayerStats struct {
+	Stats Stats `json:"stats"`
+}
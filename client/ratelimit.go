@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst.
+// A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// DefaultRateLimit is the budget shared by all endpoints.
+func DefaultRateLimit() Limit {
+	return Limit{Rate: 5, Burst: 5}
+}
+
+// DefaultEndpointLimits keeps status polling from starving shots and
+// throttles game creation.
+func DefaultEndpointLimits() map[string]Limit {
+	return map[string]Limit{
+		"InitGame":   {Rate: 1, Burst: 1},
+		"GetStatus":  {Rate: 3, Burst: 3},
+		"GetPlayers": {Rate: 2, Burst: 2},
+	}
+}
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(limit Limit, now time.Time) *bucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+}
+
+// wait returns how long until a token is available.
+func (b *bucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.limit.Rate * float64(time.Second))
+}
+
+// RateLimiter is a client side token bucket limiter with one global bucket
+// and optional per endpoint buckets. Requests only wait once a budget is
+// exhausted.
+type RateLimiter struct {
+	mu        sync.Mutex
+	global    *bucket
+	endpoints map[string]*bucket
+}
+
+func NewRateLimiter(global Limit, endpoints map[string]Limit) *RateLimiter {
+	now := time.Now()
+	l := &RateLimiter{endpoints: make(map[string]*bucket)}
+	if global.Rate > 0 {
+		l.global = newBucket(global, now)
+	}
+	for name, limit := range endpoints {
+		if limit.Rate > 0 {
+			l.endpoints[name] = newBucket(limit, now)
+		}
+	}
+	return l
+}
+
+// Wait blocks until both the global and the endpoint budget allow a request.
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string) error {
+	for {
+		delay := l.reserve(endpoint)
+		if delay == 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve takes a token from every bucket involved or returns the time to
+// wait before trying again.
+func (l *RateLimiter) reserve(endpoint string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	buckets := make([]*bucket, 0, 2)
+	if l.global != nil {
+		buckets = append(buckets, l.global)
+	}
+	if b, ok := l.endpoints[endpoint]; ok {
+		buckets = append(buckets, b)
+	}
+	var delay time.Duration
+	for _, b := range buckets {
+		b.refill(now)
+		if w := b.wait(); w > delay {
+			delay = w
+		}
+	}
+	if delay > 0 {
+		return delay
+	}
+	for _, b := range buckets {
+		b.tokens--
+	}
+	return 0
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterReserve(t *testing.T) {
+	tests := []struct {
+		name      string
+		global    Limit
+		endpoints map[string]Limit
+		endpoint  string
+		free      int
+	}{
+		{name: "global burst", global: Limit{Rate: 1, Burst: 3}, endpoint: "Shoot", free: 3},
+		{name: "endpoint burst", global: Limit{Rate: 1, Burst: 5}, endpoints: map[string]Limit{"GetStatus": {Rate: 1, Burst: 2}}, endpoint: "GetStatus", free: 2},
+		{name: "other endpoint", global: Limit{Rate: 1, Burst: 4}, endpoints: map[string]Limit{"GetStatus": {Rate: 1, Burst: 2}}, endpoint: "Shoot", free: 4},
+	}
+	for _, tt := range tests {
+		l := NewRateLimiter(tt.global, tt.endpoints)
+		for i := 0; i < tt.free; i++ {
+			if d := l.reserve(tt.endpoint); d != 0 {
+				t.Fatalf("%s: request %d delayed by %v", tt.name, i+1, d)
+			}
+		}
+		if d := l.reserve(tt.endpoint); d <= 0 || d > time.Second {
+			t.Errorf("%s: request over budget delayed by %v, want up to 1s", tt.name, d)
+		}
+	}
+}
+
+func TestRateLimiterUnlimited(t *testing.T) {
+	l := NewRateLimiter(Limit{}, nil)
+	for i := 0; i < 100; i++ {
+		if d := l.reserve("Shoot"); d != 0 {
+			t.Fatalf("request %d delayed by %v without limits", i+1, d)
+		}
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := NewRateLimiter(Limit{Rate: 0.1, Burst: 1}, nil)
+	if err := l.Wait(context.Background(), "Shoot"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "Shoot"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() = %v, want context.DeadlineExceeded", err)
+	}
+}
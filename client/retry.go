@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Attempt describes a failed request attempt handed to a RetryPolicy.
+type Attempt struct {
+	Endpoint   string
+	Number     int
+	Err        error
+	Elapsed    time.Duration
+	Idempotent bool
+	// Responded is true when the server answered, i.e. a non-idempotent
+	// request may already have been applied.
+	Responded  bool
+	RetryAfter time.Duration
+}
+
+// RetryPolicy decides whether a failed attempt is retried and how long to
+// wait before the next one.
+type RetryPolicy interface {
+	Next(attempt Attempt) (time.Duration, bool)
+}
+
+// BackoffPolicy retries with exponential backoff and jitter. Client errors
+// are never retried except 408 and 429, and non-idempotent requests are
+// only retried when they never reached the server or it asked to slow down.
+type BackoffPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Multiplier  float64
+	// Jitter spreads each delay by up to this fraction in both directions.
+	Jitter float64
+	// MaxElapsed caps the total time spent on a request, zero means no cap.
+	MaxElapsed time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used against the public server.
+func DefaultRetryPolicy() *BackoffPolicy {
+	return &BackoffPolicy{
+		MaxAttempts: maxRequests,
+		BaseDelay:   requestDelay,
+		MaxDelay:    maxRetryDelay,
+		Multiplier:  2,
+		Jitter:      0.2,
+		MaxElapsed:  maxRetryElapsed,
+	}
+}
+
+func (p *BackoffPolicy) Next(attempt Attempt) (time.Duration, bool) {
+	if attempt.Number >= p.MaxAttempts || !Retryable(attempt) {
+		return 0, false
+	}
+	delay := attempt.RetryAfter
+	if delay == 0 {
+		delay = p.backoff(attempt.Number)
+	}
+	if p.MaxElapsed > 0 && attempt.Elapsed+delay > p.MaxElapsed {
+		return 0, false
+	}
+	return delay, true
+}
+
+func (p *BackoffPolicy) backoff(n int) time.Duration {
+	mult := p.Multiplier
+	if mult < 1 {
+		mult = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(mult, float64(n-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay)
+}
+
+// Retryable classifies a failed attempt independently of attempt counts.
+func Retryable(attempt Attempt) bool {
+	if errors.Is(attempt.Err, context.Canceled) || errors.Is(attempt.Err, context.DeadlineExceeded) || errors.Is(attempt.Err, ErrNoToken) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(attempt.Err, &apiErr) {
+		switch {
+		case apiErr.StatusCode == http.StatusTooManyRequests:
+			return true
+		case apiErr.StatusCode == http.StatusRequestTimeout, apiErr.StatusCode >= 500:
+			return attempt.Idempotent
+		default:
+			return false
+		}
+	}
+	// a shot or new game may have been applied unless the connection
+	// was never made, even when the answer got lost
+	return attempt.Idempotent || (!attempt.Responded && notSent(attempt.Err))
+}
+
+// notSent reports whether err shows the request never reached the server:
+// the address did not resolve or the connection could not be made.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr)
+}
+
+// RetryError is returned once a request is given up on.
+type RetryError struct {
+	Endpoint string
+	Attempts int
+	Elapsed  time.Duration
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%s: request failed after %d attempts in %v; %v", e.Endpoint, e.Attempts, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// respondedError marks failures that happened after the server answered
+// with 200, e.g. an undecodable body.
+type respondedError struct {
+	err error
+}
+
+func (e *respondedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *respondedError) Unwrap() error {
+	return e.err
+}
+
+func responded(err error) bool {
+	var apiErr *APIError
+	var respErr *respondedError
+	return errors.As(err, &apiErr) || errors.As(err, &respErr)
+}
+
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryable(t *testing.T) {
+	apiErr := func(status int) error {
+		return &APIError{StatusCode: status}
+	}
+	tests := []struct {
+		name    string
+		attempt Attempt
+		want    bool
+	}{
+		{name: "cancelled", attempt: Attempt{Err: context.Canceled, Idempotent: true}, want: false},
+		{name: "no token", attempt: Attempt{Err: ErrNoToken, Idempotent: true}, want: false},
+		{name: "rate limited", attempt: Attempt{Err: apiErr(http.StatusTooManyRequests), Responded: true}, want: true},
+		{name: "5xx idempotent", attempt: Attempt{Err: apiErr(http.StatusBadGateway), Idempotent: true, Responded: true}, want: true},
+		{name: "5xx shot", attempt: Attempt{Err: apiErr(http.StatusBadGateway), Responded: true}, want: false},
+		{name: "request timeout", attempt: Attempt{Err: apiErr(http.StatusRequestTimeout), Idempotent: true, Responded: true}, want: true},
+		{name: "bad request", attempt: Attempt{Err: apiErr(http.StatusBadRequest), Idempotent: true, Responded: true}, want: false},
+		{name: "network idempotent", attempt: Attempt{Err: errors.New("reset"), Idempotent: true}, want: true},
+		{name: "connection lost on shot", attempt: Attempt{Err: errors.New("reset")}, want: false},
+		{name: "shot not sent", attempt: Attempt{Err: fmt.Errorf("Shoot: %w", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED})}, want: true},
+		{name: "shot host unknown", attempt: Attempt{Err: &net.DNSError{Err: "no such host", IsNotFound: true}}, want: true},
+		{name: "shot read failed", attempt: Attempt{Err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}}, want: false},
+		{name: "shot answered", attempt: Attempt{Err: errors.New("bad body"), Responded: true}, want: false},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.attempt); got != tt.want {
+			t.Errorf("%s: Retryable() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffPolicyNext(t *testing.T) {
+	p := &BackoffPolicy{MaxAttempts: 4, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond, Multiplier: 2, MaxElapsed: time.Second}
+	netErr := errors.New("reset")
+	tests := []struct {
+		name    string
+		attempt Attempt
+		delay   time.Duration
+		retry   bool
+	}{
+		{name: "first", attempt: Attempt{Number: 1, Err: netErr, Idempotent: true}, delay: 100 * time.Millisecond, retry: true},
+		{name: "second", attempt: Attempt{Number: 2, Err: netErr, Idempotent: true}, delay: 200 * time.Millisecond, retry: true},
+		{name: "capped", attempt: Attempt{Number: 3, Err: netErr, Idempotent: true}, delay: 300 * time.Millisecond, retry: true},
+		{name: "out of attempts", attempt: Attempt{Number: 4, Err: netErr, Idempotent: true}},
+		{name: "retry after", attempt: Attempt{Number: 1, Err: netErr, Idempotent: true, RetryAfter: 700 * time.Millisecond}, delay: 700 * time.Millisecond, retry: true},
+		{name: "over budget", attempt: Attempt{Number: 1, Err: netErr, Idempotent: true, Elapsed: 950 * time.Millisecond}},
+		{name: "not retryable", attempt: Attempt{Number: 1, Err: context.Canceled, Idempotent: true}},
+	}
+	for _, tt := range tests {
+		delay, retry := p.Next(tt.attempt)
+		if delay != tt.delay || retry != tt.retry {
+			t.Errorf("%s: Next() = %v, %t, want %v, %t", tt.name, delay, retry, tt.delay, tt.retry)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: 0},
+		{value: "3", want: 3 * time.Second},
+		{value: "-1", want: 0},
+		{value: now.Add(10 * time.Second).Format(http.TimeFormat), want: 10 * time.Second},
+		{value: now.Add(-10 * time.Second).Format(http.TimeFormat), want: 0},
+		{value: "soon", want: 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+// TestDroppedConnection checks that a request the server applied is not
+// sent again when the connection drops before the answer arrives.
+func TestDroppedConnection(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(c *Client) error
+		want int32
+	}{
+		{name: "shot", call: func(c *Client) error { _, err := c.Shoot(context.Background(), "A1"); return err }, want: 1},
+		{name: "new game", call: func(c *Client) error { _, err := c.InitGame(context.Background(), Game{WPBot: true}); return err }, want: 1},
+		{name: "status", call: func(c *Client) error { _, err := c.GetStatus(context.Background()); return err }, want: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var applied atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				applied.Add(1)
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				conn.Close()
+			}))
+			defer ts.Close()
+			policy := &BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Multiplier: 1}
+			c := NewClient(WithBaseURL(ts.URL), WithRetryPolicy(policy), WithRateLimits(Limit{}, nil))
+			c.SetToken("token")
+			if err := tt.call(c); err == nil {
+				t.Fatal("request succeeded on a dropped connection")
+			}
+			if got := applied.Load(); got != tt.want {
+				t.Errorf("server got %d requests, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRefusedConnectionRetried(t *testing.T) {
+	ts := httptest.NewServer(http.NotFoundHandler())
+	url := ts.URL
+	ts.Close()
+	policy := &BackoffPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, Multiplier: 1}
+	c := NewClient(WithBaseURL(url), WithRetryPolicy(policy), WithRateLimits(Limit{}, nil))
+	c.SetToken("token")
+	_, err := c.Shoot(context.Background(), "A1")
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) || retryErr.Attempts != 3 {
+		t.Fatalf("Shoot() error = %v, want a refused shot tried 3 times", err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"time"
+
+	"main/app"
+	"main/client"
+)
+
+// Environment variables read before flags; flags win over the environment.
+const (
+	envBaseURL    = "STATKI_BASE_URL"
+	envTimeout    = "STATKI_TIMEOUT"
+	envRetries    = "STATKI_RETRIES"
+	envRetryDelay = "STATKI_RETRY_DELAY"
+	envRetryMax   = "STATKI_RETRY_MAX"
+	envRate       = "STATKI_RATE"
+	envBurst      = "STATKI_BURST"
+	envPollRate   = "STATKI_POLL_RATE"
+	envBotOffer   = "STATKI_BOT_OFFER"
+)
+
+type clientConfig struct {
+	baseURL    string
+	timeout    time.Duration
+	retries    int
+	retryDelay time.Duration
+	retryMax   time.Duration
+	rate       float64
+	burst      int
+	pollRate   float64
+}
+
+// registerClientFlags adds the client flags to fs with defaults taken from
+// the environment.
+func registerClientFlags(fs *flag.FlagSet) *clientConfig {
+	limit := client.DefaultRateLimit()
+	retry := client.DefaultRetryPolicy()
+	cfg := &clientConfig{}
+	fs.StringVar(&cfg.baseURL, "url", envString(envBaseURL, client.DefaultBaseURL), "server base URL ($"+envBaseURL+")")
+	fs.DurationVar(&cfg.timeout, "timeout", envDuration(envTimeout, 30*time.Second), "HTTP request timeout ($"+envTimeout+")")
+	fs.IntVar(&cfg.retries, "retries", envInt(envRetries, retry.MaxAttempts), "attempts per request ($"+envRetries+")")
+	fs.DurationVar(&cfg.retryDelay, "retry-delay", envDuration(envRetryDelay, retry.BaseDelay), "initial backoff between attempts ($"+envRetryDelay+")")
+	fs.DurationVar(&cfg.retryMax, "retry-max", envDuration(envRetryMax, retry.MaxElapsed), "total time spent retrying a request ($"+envRetryMax+")")
+	fs.Float64Var(&cfg.rate, "rate", envFloat(envRate, limit.Rate), "requests per second shared by all endpoints, 0 = unlimited ($"+envRate+")")
+	fs.IntVar(&cfg.burst, "burst", envInt(envBurst, limit.Burst), "requests allowed at once before rate limiting ($"+envBurst+")")
+	fs.Float64Var(&cfg.pollRate, "poll-rate", envFloat(envPollRate, client.DefaultEndpointLimits()["GetStatus"].Rate), "status polls per second ($"+envPollRate+")")
+	return cfg
+}
+
+func (cfg *clientConfig) options() []client.Option {
+	endpoints := client.DefaultEndpointLimits()
+	endpoints["GetStatus"] = client.Limit{Rate: cfg.pollRate, Burst: endpoints["GetStatus"].Burst}
+	retry := client.DefaultRetryPolicy()
+	retry.MaxAttempts = cfg.retries
+	retry.BaseDelay = cfg.retryDelay
+	retry.MaxElapsed = cfg.retryMax
+	return []client.Option{
+		client.WithBaseURL(cfg.baseURL),
+		client.WithTimeout(cfg.timeout),
+		client.WithRetryPolicy(retry),
+		client.WithRateLimits(client.Limit{Rate: cfg.rate, Burst: cfg.burst}, endpoints),
+	}
+}
+
+func (cfg *clientConfig) newAPI() client.GameAPI {
+	return client.NewClient(cfg.options()...)
+}
+
+// appConfig holds the settings of the terminal UI.
+type appConfig struct {
+	botOffer time.Duration
+}
+
+func registerAppFlags(fs *flag.FlagSet) *appConfig {
+	cfg := &appConfig{}
+	fs.DurationVar(&cfg.botOffer, "bot-offer", envDuration(envBotOffer, time.Minute), "wait for an opponent this long before offering a bot game ($"+envBotOffer+")")
+	return cfg
+}
+
+func (cfg *appConfig) newApp(clientCfg *clientConfig) *app.App {
+	return &app.App{NewAPI: clientCfg.newAPI, BotOfferAfter: cfg.botOffer}
+}
+
+func envString(key, def string) string {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v
+	}
+	return def
+}
+
+func envInt(key string, def int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+func envFloat(key string, def float64) float64 {
+	if v, err := strconv.ParseFloat(os.Getenv(key), 64); err == nil {
+		return v
+	}
+	return def
+}
+
+func envDuration(key string, def time.Duration) time.Duration {
+	if v, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return v
+	}
+	return def
+}
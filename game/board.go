@@ -0,0 +1,133 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrAlreadyFired = errors.New("cell already fired at")
+
+// Cell is the state of a single board field.
+type Cell int
+
+const (
+	CellEmpty Cell = iota
+	CellShip
+	CellHit
+	CellMiss
+)
+
+// Result is the outcome of a shot, spelled like the server does.
+type Result string
+
+const (
+	ResultMiss Result = "miss"
+	ResultHit  Result = "hit"
+	ResultSunk Result = "sunk"
+)
+
+// Board is a 10x10 grid indexed [X][Y]. It is used both for our own fleet,
+// where ship cells turn into hits, and for tracking shots at the opponent.
+type Board struct {
+	cells [Size][Size]Cell
+}
+
+// NewBoard returns a board with a ship cell on every given coordinate.
+func NewBoard(ships []Coord) *Board {
+	b := &Board{}
+	for _, c := range ships {
+		b.Set(c, CellShip)
+	}
+	return b
+}
+
+func (b *Board) At(c Coord) Cell {
+	if !c.Valid() {
+		return CellEmpty
+	}
+	return b.cells[c.X][c.Y]
+}
+
+func (b *Board) Set(c Coord, cell Cell) {
+	if c.Valid() {
+		b.cells[c.X][c.Y] = cell
+	}
+}
+
+// Cells returns every coordinate holding one of the given states.
+func (b *Board) Cells(states ...Cell) []Coord {
+	var res []Coord
+	for _, c := range AllCoords() {
+		for _, s := range states {
+			if b.At(c) == s {
+				res = append(res, c)
+				break
+			}
+		}
+	}
+	return res
+}
+
+// Shot reports whether c was already fired at.
+func (b *Board) Shot(c Coord) bool {
+	cell := b.At(c)
+	return cell == CellHit || cell == CellMiss
+}
+
+// ShipAt returns the orthogonally connected ship and hit cells containing c.
+func (b *Board) ShipAt(c Coord) []Coord {
+	if cell := b.At(c); cell != CellShip && cell != CellHit {
+		return nil
+	}
+	seen := map[Coord]bool{c: true}
+	ship := []Coord{c}
+	for i := 0; i < len(ship); i++ {
+		for _, n := range ship[i].Orthogonal() {
+			if cell := b.At(n); (cell == CellShip || cell == CellHit) && !seen[n] {
+				seen[n] = true
+				ship = append(ship, n)
+			}
+		}
+	}
+	return ship
+}
+
+// Fire applies a shot to a board holding a fleet. Cells already fired at
+// return ErrAlreadyFired and leave the board unchanged.
+func (b *Board) Fire(c Coord) (Result, error) {
+	switch b.At(c) {
+	case CellShip:
+		b.Set(c, CellHit)
+	case CellHit, CellMiss:
+		return "", fmt.Errorf("%w: %s", ErrAlreadyFired, c)
+	default:
+		b.Set(c, CellMiss)
+		return ResultMiss, nil
+	}
+	for _, s := range b.ShipAt(c) {
+		if b.At(s) == CellShip {
+			return ResultHit, nil
+		}
+	}
+	return ResultSunk, nil
+}
+
+// Defeated reports whether no ship cell is left.
+func (b *Board) Defeated() bool {
+	return len(b.Cells(CellShip)) == 0
+}
+
+// MarkSunk marks every empty cell around the sunk ship containing c as a
+// miss, as nothing can be there. It returns the newly marked cells.
+func (b *Board) MarkSunk(c Coord) []Coord {
+	var marked []Coord
+	for _, s := range b.ShipAt(c) {
+		for _, n := range s.Neighbors() {
+			if b.At(n) == CellEmpty {
+				b.Set(n, CellMiss)
+				marked = append(marked, n)
+			}
+		}
+	}
+	return marked
+}
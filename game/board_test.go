@@ -0,0 +1,55 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBoardFire(t *testing.T) {
+	// a two cell ship at A1-B1 and a single cell one at D4
+	b := NewBoard([]Coord{{0, 0}, {1, 0}, {3, 3}})
+	tests := []struct {
+		shot Coord
+		want Result
+		err  error
+	}{
+		{shot: Coord{0, 0}, want: ResultHit},
+		{shot: Coord{0, 0}, err: ErrAlreadyFired},
+		{shot: Coord{5, 5}, want: ResultMiss},
+		{shot: Coord{5, 5}, err: ErrAlreadyFired},
+		{shot: Coord{1, 0}, want: ResultSunk},
+		{shot: Coord{1, 0}, err: ErrAlreadyFired},
+		{shot: Coord{3, 3}, want: ResultSunk},
+	}
+	for i, tt := range tests {
+		got, err := b.Fire(tt.shot)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%d: Fire(%s) error = %v, want %v", i, tt.shot, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%d: Fire(%s) = %q, %v, want %q", i, tt.shot, got, err, tt.want)
+		}
+	}
+	if !b.Defeated() {
+		t.Error("Defeated() = false after sinking every ship")
+	}
+}
+
+func TestBoardMarkSunk(t *testing.T) {
+	b := NewBoard([]Coord{{0, 0}, {1, 0}})
+	b.Fire(Coord{0, 0})
+	b.Fire(Coord{1, 0})
+	marked := b.MarkSunk(Coord{0, 0})
+	// A2, B2, C2 and C1 surround the ship in the corner
+	if len(marked) != 4 {
+		t.Errorf("MarkSunk marked %v, want 4 cells", marked)
+	}
+	for _, c := range marked {
+		if b.At(c) != CellMiss {
+			t.Errorf("%s = %v, want CellMiss", c, b.At(c))
+		}
+	}
+}
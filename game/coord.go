@@ -0,0 +1,117 @@
+// Package game holds the warships rules shared by the app, bots and the
+// local server: coordinates, boards, ships and fleets.
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is the width and height of a board.
+const Size = 10
+
+var ErrInvalidCoord = errors.New("invalid coordinate")
+
+// Coord is a zero based board position, X is the column (A..J) and Y the
+// row (1..10 in text form).
+type Coord struct {
+	X int
+	Y int
+}
+
+// ParseCoord parses "A1".."J10", case insensitive. The row is plain
+// digits without a leading zero.
+func ParseCoord(s string) (Coord, error) {
+	if len(s) < 2 || len(s) > 3 || s[1] == '0' || strings.Trim(s[1:], "0123456789") != "" {
+		return Coord{}, fmt.Errorf("%w: %q", ErrInvalidCoord, s)
+	}
+	col := strings.ToUpper(s[:1])[0]
+	row, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return Coord{}, fmt.Errorf("%w: %q", ErrInvalidCoord, s)
+	}
+	c := Coord{X: int(col - 'A'), Y: row - 1}
+	if !c.Valid() {
+		return Coord{}, fmt.Errorf("%w: %q", ErrInvalidCoord, s)
+	}
+	return c, nil
+}
+
+// ParseCoords parses every coordinate, failing on the first invalid one.
+func ParseCoords(coords []string) ([]Coord, error) {
+	res := make([]Coord, 0, len(coords))
+	for _, s := range coords {
+		c, err := ParseCoord(s)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+// FormatCoords is the inverse of ParseCoords.
+func FormatCoords(coords []Coord) []string {
+	res := make([]string, 0, len(coords))
+	for _, c := range coords {
+		res = append(res, c.String())
+	}
+	return res
+}
+
+func (c Coord) String() string {
+	if !c.Valid() {
+		return fmt.Sprintf("(%d,%d)", c.X, c.Y)
+	}
+	return fmt.Sprintf("%c%d", 'A'+c.X, c.Y+1)
+}
+
+// Valid reports whether c lies on the board.
+func (c Coord) Valid() bool {
+	return c.X >= 0 && c.X < Size && c.Y >= 0 && c.Y < Size
+}
+
+// Add returns c moved by dx, dy. The result may be off the board.
+func (c Coord) Add(dx, dy int) Coord {
+	return Coord{X: c.X + dx, Y: c.Y + dy}
+}
+
+// Neighbors returns the up to 8 surrounding coordinates on the board.
+func (c Coord) Neighbors() []Coord {
+	res := make([]Coord, 0, 8)
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			if dx == 0 && dy == 0 {
+				continue
+			}
+			if n := c.Add(dx, dy); n.Valid() {
+				res = append(res, n)
+			}
+		}
+	}
+	return res
+}
+
+// Orthogonal returns the up to 4 edge sharing coordinates on the board.
+func (c Coord) Orthogonal() []Coord {
+	res := make([]Coord, 0, 4)
+	for _, d := range [4][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+		if n := c.Add(d[0], d[1]); n.Valid() {
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// AllCoords returns every coordinate of the board, column by column.
+func AllCoords() []Coord {
+	res := make([]Coord, 0, Size*Size)
+	for x := 0; x < Size; x++ {
+		for y := 0; y < Size; y++ {
+			res = append(res, Coord{X: x, Y: y})
+		}
+	}
+	return res
+}
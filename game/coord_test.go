@@ -0,0 +1,42 @@
+package game
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseCoord(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Coord
+		err  bool
+	}{
+		{in: "A1", want: Coord{X: 0, Y: 0}},
+		{in: "j10", want: Coord{X: 9, Y: 9}},
+		{in: "E7", want: Coord{X: 4, Y: 6}},
+		{in: "A01", err: true},
+		{in: "A+1", err: true},
+		{in: "A0", err: true},
+		{in: "A11", err: true},
+		{in: "K1", err: true},
+		{in: "A 1", err: true},
+		{in: "A", err: true},
+		{in: "A100", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCoord(tt.in)
+		if tt.err {
+			if !errors.Is(err, ErrInvalidCoord) {
+				t.Errorf("ParseCoord(%q) error = %v, want ErrInvalidCoord", tt.in, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseCoord(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+		if got.String() != strings.ToUpper(tt.in) {
+			t.Errorf("ParseCoord(%q).String() = %s", tt.in, got)
+		}
+	}
+}
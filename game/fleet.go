@@ -0,0 +1,150 @@
+package game
+
+import (
+	"errors"
+	"sort"
+)
+
+// ClassicFleet maps ship length to the number of ships of that length.
+var ClassicFleet = map[int]int{4: 1, 3: 2, 2: 3, 1: 4}
+
+// FleetSizes lists the ship lengths of ClassicFleet, longest first.
+var FleetSizes = []int{4, 3, 3, 2, 2, 2, 1, 1, 1, 1}
+
+// FleetCells is the number of cells occupied by ClassicFleet.
+const FleetCells = 20
+
+var ErrInvalidFleet = errors.New("invalid fleet")
+
+// Ship is a set of orthogonally connected cells.
+type Ship struct {
+	Cells []Coord
+}
+
+func (s Ship) Len() int {
+	return len(s.Cells)
+}
+
+// Straight reports whether all cells share a row or a column without gaps.
+func (s Ship) Straight() bool {
+	if len(s.Cells) == 0 {
+		return false
+	}
+	minX, maxX, minY, maxY := s.Cells[0].X, s.Cells[0].X, s.Cells[0].Y, s.Cells[0].Y
+	for _, c := range s.Cells[1:] {
+		if c.X < minX {
+			minX = c.X
+		}
+		if c.X > maxX {
+			maxX = c.X
+		}
+		if c.Y < minY {
+			minY = c.Y
+		}
+		if c.Y > maxY {
+			maxY = c.Y
+		}
+	}
+	if minX != maxX && minY != maxY {
+		return false
+	}
+	return (maxX-minX)+(maxY-minY)+1 == len(s.Cells)
+}
+
+func (s Ship) Contains(c Coord) bool {
+	for _, sc := range s.Cells {
+		if sc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// Fleet is every ship placed on one board.
+type Fleet struct {
+	Ships []Ship
+}
+
+// NewFleet groups coordinates into ships of orthogonally connected cells.
+// Duplicates are ignored.
+func NewFleet(coords []Coord) Fleet {
+	b := NewBoard(coords)
+	seen := make(map[Coord]bool)
+	var fleet Fleet
+	for _, c := range b.Cells(CellShip) {
+		if seen[c] {
+			continue
+		}
+		ship := b.ShipAt(c)
+		sort.Slice(ship, func(i, j int) bool {
+			if ship[i].X != ship[j].X {
+				return ship[i].X < ship[j].X
+			}
+			return ship[i].Y < ship[j].Y
+		})
+		for _, sc := range ship {
+			seen[sc] = true
+		}
+		fleet.Ships = append(fleet.Ships, Ship{Cells: ship})
+	}
+	sort.SliceStable(fleet.Ships, func(i, j int) bool {
+		return fleet.Ships[i].Len() > fleet.Ships[j].Len()
+	})
+	return fleet
+}
+
+// ParseFleet parses coordinates and groups them into ships.
+func ParseFleet(coords []string) (Fleet, error) {
+	parsed, err := ParseCoords(coords)
+	if err != nil {
+		return Fleet{}, err
+	}
+	return NewFleet(parsed), nil
+}
+
+// Coords returns the cells of every ship.
+func (f Fleet) Coords() []Coord {
+	var res []Coord
+	for _, s := range f.Ships {
+		res = append(res, s.Cells...)
+	}
+	return res
+}
+
+// Validate checks the fleet against ClassicFleet, see ValidateLayout.
+func (f Fleet) Validate() error {
+	if vs := ValidateLayout(f.Coords()); len(vs) > 0 {
+		return vs
+	}
+	return nil
+}
+
+// Line returns size cells starting at start, going right when horizontal
+// and down otherwise.
+func Line(start Coord, size int, horizontal bool) []Coord {
+	cells := make([]Coord, 0, size)
+	for i := 0; i < size; i++ {
+		if horizontal {
+			cells = append(cells, start.Add(i, 0))
+		} else {
+			cells = append(cells, start.Add(0, i))
+		}
+	}
+	return cells
+}
+
+// CanPlace reports whether ship fits on b without leaving the board or
+// touching another ship, even by a corner.
+func CanPlace(b *Board, ship []Coord) bool {
+	for _, c := range ship {
+		if !c.Valid() || b.At(c) != CellEmpty {
+			return false
+		}
+		for _, n := range c.Neighbors() {
+			if b.At(n) != CellEmpty {
+				return false
+			}
+		}
+	}
+	return true
+}
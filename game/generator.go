@@ -0,0 +1,225 @@
+package game
+
+import (
+	"math/rand"
+	"time"
+)
+
+// EdgeBias steers ships towards or away from the board edges.
+type EdgeBias int
+
+const (
+	EdgeNeutral EdgeBias = iota
+	EdgeAvoid
+	EdgeHug
+)
+
+func (e EdgeBias) String() string {
+	switch e {
+	case EdgeAvoid:
+		return "avoid edges"
+	case EdgeHug:
+		return "hug edges"
+	default:
+		return "any edges"
+	}
+}
+
+// Strategy decides how ships are spread over the board.
+type Strategy int
+
+const (
+	StrategyUniform Strategy = iota
+	// StrategySpread keeps ships far from each other.
+	StrategySpread
+	// StrategyClustered packs ships close together.
+	StrategyClustered
+	// StrategyAntiProbability prefers cells a density based shooter is
+	// least likely to try early.
+	StrategyAntiProbability
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case StrategySpread:
+		return "spread"
+	case StrategyClustered:
+		return "clustered"
+	case StrategyAntiProbability:
+		return "anti-probability"
+	default:
+		return "uniform"
+	}
+}
+
+const defaultCandidates = 12
+
+// GeneratorOptions configure a Generator. A zero Seed picks a time based one.
+type GeneratorOptions struct {
+	Seed     int64
+	Edge     EdgeBias
+	Strategy Strategy
+	// Candidates is how many legal placements are scored per ship when a
+	// bias or strategy is set, more means a stronger bias.
+	Candidates int
+}
+
+// Generator produces valid ClassicFleet layouts.
+type Generator struct {
+	opts GeneratorOptions
+	rng  *rand.Rand
+}
+
+func NewGenerator(opts GeneratorOptions) *Generator {
+	if opts.Seed == 0 {
+		opts.Seed = time.Now().UnixNano()
+	}
+	if opts.Candidates <= 0 {
+		opts.Candidates = defaultCandidates
+	}
+	return &Generator{opts: opts, rng: rand.New(rand.NewSource(opts.Seed))}
+}
+
+// Seed returns the seed the generator was created with.
+func (g *Generator) Seed() int64 {
+	return g.opts.Seed
+}
+
+// RandomFleet places ClassicFleet uniformly at random using rng.
+func RandomFleet(rng *rand.Rand) []Coord {
+	g := &Generator{opts: GeneratorOptions{Candidates: 1}, rng: rng}
+	return g.Generate()
+}
+
+// Generate returns the cells of a valid fleet, longest ships first.
+func (g *Generator) Generate() []Coord {
+	for {
+		if coords, ok := g.try(); ok {
+			return coords
+		}
+	}
+}
+
+func (g *Generator) try() ([]Coord, bool) {
+	b := &Board{}
+	coords := make([]Coord, 0, FleetCells)
+	for _, size := range FleetSizes {
+		options := Placements(b, size)
+		if len(options) == 0 {
+			return nil, false
+		}
+		ship := g.pick(options, coords)
+		for _, c := range ship {
+			b.Set(c, CellShip)
+		}
+		coords = append(coords, ship...)
+	}
+	return coords, true
+}
+
+// pick chooses one placement. Without a bias it is uniform, otherwise the
+// best scored of a few random candidates wins.
+func (g *Generator) pick(options [][]Coord, placed []Coord) []Coord {
+	if g.opts.Edge == EdgeNeutral && g.opts.Strategy == StrategyUniform {
+		return options[g.rng.Intn(len(options))]
+	}
+	var best []Coord
+	bestScore := 0.0
+	for i := 0; i < g.opts.Candidates; i++ {
+		ship := options[g.rng.Intn(len(options))]
+		score := g.score(ship, placed)
+		if best == nil || score > bestScore {
+			best, bestScore = ship, score
+		}
+	}
+	return best
+}
+
+func (g *Generator) score(ship, placed []Coord) float64 {
+	score := 0.0
+	for _, c := range ship {
+		edge := 0.0
+		if c.X == 0 || c.Y == 0 || c.X == Size-1 || c.Y == Size-1 {
+			edge = 1
+		}
+		switch g.opts.Edge {
+		case EdgeAvoid:
+			score -= edge
+		case EdgeHug:
+			score += edge
+		}
+		if g.opts.Strategy == StrategyAntiProbability {
+			score -= emptyDensity[c.X][c.Y] / maxEmptyDensity * 2
+		}
+	}
+	if len(placed) > 0 && (g.opts.Strategy == StrategySpread || g.opts.Strategy == StrategyClustered) {
+		d := float64(minDistance(ship, placed))
+		if g.opts.Strategy == StrategySpread {
+			score += d
+		} else {
+			score -= d
+		}
+	}
+	return score
+}
+
+// Placements lists every straight position of a ship of the given size
+// that fits on b without touching another ship.
+func Placements(b *Board, size int) [][]Coord {
+	var res [][]Coord
+	for _, start := range AllCoords() {
+		for _, horizontal := range []bool{true, false} {
+			if size == 1 && !horizontal {
+				continue
+			}
+			ship := Line(start, size, horizontal)
+			if CanPlace(b, ship) {
+				res = append(res, ship)
+			}
+		}
+	}
+	return res
+}
+
+// minDistance is the smallest Chebyshev distance between two cell sets.
+func minDistance(a, b []Coord) int {
+	best := Size * 2
+	for _, p := range a {
+		for _, q := range b {
+			dx, dy := p.X-q.X, p.Y-q.Y
+			if dx < 0 {
+				dx = -dx
+			}
+			if dy < 0 {
+				dy = -dy
+			}
+			d := dx
+			if dy > d {
+				d = dy
+			}
+			if d < best {
+				best = d
+			}
+		}
+	}
+	return best
+}
+
+// emptyDensity counts, for every cell, how many ClassicFleet ship
+// placements on an empty board cover it. Density based shooters open on
+// the highest counts.
+var emptyDensity, maxEmptyDensity = func() ([Size][Size]float64, float64) {
+	var d [Size][Size]float64
+	max := 0.0
+	for _, size := range FleetSizes {
+		for _, ship := range Placements(&Board{}, size) {
+			for _, c := range ship {
+				d[c.X][c.Y]++
+				if d[c.X][c.Y] > max {
+					max = d[c.X][c.Y]
+				}
+			}
+		}
+	}
+	return d, max
+}()
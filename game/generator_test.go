@@ -0,0 +1,58 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGeneratorValid(t *testing.T) {
+	tests := []GeneratorOptions{
+		{},
+		{Edge: EdgeAvoid},
+		{Edge: EdgeHug},
+		{Strategy: StrategySpread},
+		{Strategy: StrategyClustered},
+		{Strategy: StrategyAntiProbability, Edge: EdgeHug},
+	}
+	for _, opts := range tests {
+		opts.Seed = 42
+		g := NewGenerator(opts)
+		for i := 0; i < 20; i++ {
+			if vs := ValidateLayout(g.Generate()); len(vs) > 0 {
+				t.Fatalf("%s, %s: invalid layout: %v", opts.Strategy, opts.Edge, vs)
+			}
+		}
+	}
+}
+
+func TestGeneratorSeed(t *testing.T) {
+	a := NewGenerator(GeneratorOptions{Seed: 7, Strategy: StrategySpread})
+	b := NewGenerator(GeneratorOptions{Seed: 7, Strategy: StrategySpread})
+	if !reflect.DeepEqual(a.Generate(), b.Generate()) {
+		t.Error("generators with the same seed produced different layouts")
+	}
+	if a.Seed() != 7 {
+		t.Errorf("Seed() = %d, want 7", a.Seed())
+	}
+	if NewGenerator(GeneratorOptions{}).Seed() == 0 {
+		t.Error("zero seed was not replaced")
+	}
+}
+
+func TestGeneratorEdgeBias(t *testing.T) {
+	edgeCells := func(edge EdgeBias) int {
+		g := NewGenerator(GeneratorOptions{Seed: 1, Edge: edge})
+		n := 0
+		for i := 0; i < 50; i++ {
+			for _, c := range g.Generate() {
+				if c.X == 0 || c.Y == 0 || c.X == Size-1 || c.Y == Size-1 {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if hug, avoid := edgeCells(EdgeHug), edgeCells(EdgeAvoid); hug <= avoid {
+		t.Errorf("edge cells: hug %d, avoid %d, want hug > avoid", hug, avoid)
+	}
+}
@@ -0,0 +1,118 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFleetComplete = errors.New("fleet already complete")
+	ErrIllegalShip   = errors.New("ship does not fit there")
+)
+
+// Placement builds a ClassicFleet ship by ship, longest first, with undo
+// and redo.
+type Placement struct {
+	board *Board
+	ships [][]Coord
+	redo  [][]Coord
+}
+
+func NewPlacement() *Placement {
+	return &Placement{board: &Board{}}
+}
+
+// Next returns the length of the next ship to place, false once done.
+func (p *Placement) Next() (int, bool) {
+	if p.Done() {
+		return 0, false
+	}
+	return FleetSizes[len(p.ships)], true
+}
+
+func (p *Placement) Done() bool {
+	return len(p.ships) == len(FleetSizes)
+}
+
+// Ghost returns the cells the next ship would take at the given position
+// and whether it can legally go there. Cells off the board are dropped.
+func (p *Placement) Ghost(at Coord, horizontal bool) ([]Coord, bool) {
+	size, ok := p.Next()
+	if !ok {
+		return nil, false
+	}
+	line := Line(at, size, horizontal)
+	legal := CanPlace(p.board, line)
+	cells := make([]Coord, 0, size)
+	for _, c := range line {
+		if c.Valid() {
+			cells = append(cells, c)
+		}
+	}
+	return cells, legal
+}
+
+// Place puts the next ship at the given position.
+func (p *Placement) Place(at Coord, horizontal bool) error {
+	size, ok := p.Next()
+	if !ok {
+		return ErrFleetComplete
+	}
+	ship := Line(at, size, horizontal)
+	if !CanPlace(p.board, ship) {
+		return fmt.Errorf("%w: %d long at %s", ErrIllegalShip, size, at)
+	}
+	p.push(ship)
+	p.redo = nil
+	return nil
+}
+
+func (p *Placement) push(ship []Coord) {
+	for _, c := range ship {
+		p.board.Set(c, CellShip)
+	}
+	p.ships = append(p.ships, ship)
+}
+
+// Undo removes the last placed ship.
+func (p *Placement) Undo() bool {
+	if len(p.ships) == 0 {
+		return false
+	}
+	last := p.ships[len(p.ships)-1]
+	p.ships = p.ships[:len(p.ships)-1]
+	for _, c := range last {
+		p.board.Set(c, CellEmpty)
+	}
+	p.redo = append(p.redo, last)
+	return true
+}
+
+// Redo puts back the last undone ship.
+func (p *Placement) Redo() bool {
+	if len(p.redo) == 0 {
+		return false
+	}
+	ship := p.redo[len(p.redo)-1]
+	p.redo = p.redo[:len(p.redo)-1]
+	p.push(ship)
+	return true
+}
+
+// Remaining maps ship length to how many ships of it are still to place.
+func (p *Placement) Remaining() map[int]int {
+	res := make(map[int]int)
+	for _, size := range FleetSizes[len(p.ships):] {
+		res[size]++
+	}
+	return res
+}
+
+// Coords returns the cells of every placed ship.
+func (p *Placement) Coords() []Coord {
+	var res []Coord
+	for _, s := range p.ships {
+		res = append(res, s...)
+	}
+	return res
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPlacement(t *testing.T) {
+	p := NewPlacement()
+	steps := []struct {
+		at         string
+		horizontal bool
+		err        error
+	}{
+		{at: "A1", horizontal: true},
+		{at: "A2", horizontal: true, err: ErrIllegalShip}, // touches the first ship
+		{at: "I1", horizontal: true, err: ErrIllegalShip}, // runs off the board
+		{at: "J1", horizontal: false},
+		{at: "A3", horizontal: true},
+		{at: "E3", horizontal: true},
+		{at: "A5", horizontal: true},
+		{at: "D5", horizontal: true},
+		{at: "G5", horizontal: true},
+		{at: "A7", horizontal: true},
+		{at: "C7", horizontal: true},
+		{at: "E7", horizontal: true},
+		{at: "I9", horizontal: true, err: ErrFleetComplete},
+	}
+	for _, s := range steps {
+		at, err := ParseCoord(s.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Place(at, s.horizontal); !errors.Is(err, s.err) {
+			t.Fatalf("Place(%s) = %v, want %v", s.at, err, s.err)
+		}
+	}
+	if !p.Done() {
+		t.Fatal("Done() = false after placing every ship")
+	}
+	if vs := ValidateLayout(p.Coords()); len(vs) > 0 {
+		t.Errorf("placed layout is invalid: %v", vs)
+	}
+}
+
+func TestPlacementUndoRedo(t *testing.T) {
+	p := NewPlacement()
+	if p.Undo() || p.Redo() {
+		t.Fatal("Undo or Redo succeeded on an empty placement")
+	}
+	if err := p.Place(Coord{0, 0}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Place(Coord{0, 2}, true); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Undo() {
+		t.Fatal("Undo() = false")
+	}
+	if got := p.Remaining(); got[3] != 2 || got[4] != 0 {
+		t.Errorf("Remaining() = %v after undo, want two 3 long ships", got)
+	}
+	if ghost, ok := p.Ghost(Coord{8, 5}, true); ok || len(ghost) != 2 {
+		t.Errorf("Ghost off the edge = %v, %t, want 2 cells and false", ghost, ok)
+	}
+	if !p.Redo() {
+		t.Fatal("Redo() = false")
+	}
+	if len(p.Coords()) != 7 {
+		t.Errorf("Coords() = %v, want 7 cells", p.Coords())
+	}
+	if err := p.Place(Coord{0, 4}, true); err != nil {
+		t.Fatal(err)
+	}
+	if p.Redo() {
+		t.Error("Redo() after a new placement should have nothing to redo")
+	}
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ViolationKind names a broken placement rule.
+type ViolationKind string
+
+const (
+	ViolationDuplicate ViolationKind = "duplicate"
+	ViolationCells     ViolationKind = "cell_count"
+	ViolationShape     ViolationKind = "not_straight"
+	ViolationLength    ViolationKind = "too_long"
+	ViolationContact   ViolationKind = "contact"
+	ViolationShips     ViolationKind = "ship_count"
+)
+
+// Violation is one broken rule with the cells responsible for it.
+type Violation struct {
+	Kind    ViolationKind
+	Cells   []Coord
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Message
+}
+
+// Violations is every rule a layout breaks. It is an error matching
+// ErrInvalidFleet.
+type Violations []Violation
+
+func (vs Violations) Error() string {
+	msgs := make([]string, 0, len(vs))
+	for _, v := range vs {
+		msgs = append(msgs, v.Message)
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidFleet, strings.Join(msgs, "; "))
+}
+
+func (vs Violations) Is(target error) bool {
+	return target == ErrInvalidFleet
+}
+
+// Cells returns every cell involved in any violation, without duplicates.
+func (vs Violations) Cells() []Coord {
+	seen := make(map[Coord]bool)
+	var res []Coord
+	for _, v := range vs {
+		for _, c := range v.Cells {
+			if !seen[c] {
+				seen[c] = true
+				res = append(res, c)
+			}
+		}
+	}
+	return res
+}
+
+// ValidateLayout checks coords against ClassicFleet: 20 distinct cells
+// forming straight ships of the right lengths and counts, with no two
+// ships touching by an edge or a corner. Ships touching by an edge merge
+// into one shape and are reported as bent or too long. It returns nil for
+// a valid layout.
+func ValidateLayout(coords []Coord) Violations {
+	var vs Violations
+	seen := make(map[Coord]bool)
+	for _, c := range coords {
+		if seen[c] {
+			vs = append(vs, Violation{Kind: ViolationDuplicate, Cells: []Coord{c}, Message: fmt.Sprintf("%s is used twice", c)})
+		}
+		seen[c] = true
+	}
+	if len(seen) != FleetCells {
+		vs = append(vs, Violation{Kind: ViolationCells, Message: fmt.Sprintf("fleet needs %d cells, got %d", FleetCells, len(seen))})
+	}
+
+	fleet := NewFleet(coords)
+	maxLen := 0
+	for size := range ClassicFleet {
+		if size > maxLen {
+			maxLen = size
+		}
+	}
+	counts := make(map[int][]Ship)
+	owner := make(map[Coord]int)
+	for i, s := range fleet.Ships {
+		for _, c := range s.Cells {
+			owner[c] = i
+		}
+		switch {
+		case !s.Straight():
+			vs = append(vs, Violation{Kind: ViolationShape, Cells: s.Cells, Message: fmt.Sprintf("ship at %s is not straight", s.Cells[0])})
+		case s.Len() > maxLen:
+			vs = append(vs, Violation{Kind: ViolationLength, Cells: s.Cells, Message: fmt.Sprintf("ship at %s is %d long, max is %d", s.Cells[0], s.Len(), maxLen)})
+		default:
+			counts[s.Len()] = append(counts[s.Len()], s)
+		}
+	}
+
+	touching := make(map[[2]int]bool)
+	for i, s := range fleet.Ships {
+		for _, c := range s.Cells {
+			for _, n := range c.Neighbors() {
+				j, ok := owner[n]
+				if !ok || j == i {
+					continue
+				}
+				pair := [2]int{i, j}
+				if j < i {
+					pair = [2]int{j, i}
+				}
+				if touching[pair] {
+					continue
+				}
+				touching[pair] = true
+				cells := append(append([]Coord{}, fleet.Ships[pair[0]].Cells...), fleet.Ships[pair[1]].Cells...)
+				vs = append(vs, Violation{Kind: ViolationContact, Cells: cells, Message: fmt.Sprintf("ships at %s and %s touch", fleet.Ships[pair[0]].Cells[0], fleet.Ships[pair[1]].Cells[0])})
+			}
+		}
+	}
+
+	sizes := make([]int, 0, len(ClassicFleet))
+	for size := range ClassicFleet {
+		sizes = append(sizes, size)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	for _, size := range sizes {
+		want, got := ClassicFleet[size], len(counts[size])
+		if got == want {
+			continue
+		}
+		v := Violation{Kind: ViolationShips, Message: fmt.Sprintf("need %d ships of length %d, got %d", want, size, got)}
+		if got > want {
+			for _, s := range counts[size] {
+				v.Cells = append(v.Cells, s.Cells...)
+			}
+		}
+		vs = append(vs, v)
+	}
+	return vs
+}
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+// validLayout is a ClassicFleet layout with every ship on its own row.
+var validLayout = []string{
+	"A1", "B1", "C1", "D1",
+	"A3", "B3", "C3", "E3", "F3", "G3",
+	"A5", "B5", "D5", "E5", "G5", "H5",
+	"A7", "C7", "E7", "G7",
+}
+
+func layoutWith(replace map[string]string) []Coord {
+	var res []string
+	for _, s := range validLayout {
+		if r, ok := replace[s]; ok {
+			if r == "" {
+				continue
+			}
+			s = r
+		}
+		res = append(res, s)
+	}
+	coords, err := ParseCoords(res)
+	if err != nil {
+		panic(err)
+	}
+	return coords
+}
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		coords []Coord
+		kinds  []ViolationKind
+	}{
+		{name: "valid", coords: layoutWith(nil)},
+		{name: "duplicate", coords: layoutWith(map[string]string{"G7": "E7"}), kinds: []ViolationKind{ViolationDuplicate, ViolationCells, ViolationShips}},
+		{name: "missing cell", coords: layoutWith(map[string]string{"G7": ""}), kinds: []ViolationKind{ViolationCells, ViolationShips}},
+		{name: "corner contact", coords: layoutWith(map[string]string{"G7": "I6"}), kinds: []ViolationKind{ViolationContact}},
+		{name: "bent", coords: layoutWith(map[string]string{"D1": "C2"}), kinds: []ViolationKind{ViolationShape, ViolationShips}},
+		{name: "too long", coords: layoutWith(map[string]string{"G7": "E1"}), kinds: []ViolationKind{ViolationLength, ViolationShips}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vs := ValidateLayout(tt.coords)
+			got := make(map[ViolationKind]bool)
+			for _, v := range vs {
+				got[v.Kind] = true
+			}
+			if len(got) != len(tt.kinds) {
+				t.Fatalf("ValidateLayout() = %v, want kinds %v", vs, tt.kinds)
+			}
+			for _, k := range tt.kinds {
+				if !got[k] {
+					t.Errorf("ValidateLayout() = %v, missing %s", vs, k)
+				}
+			}
+			if len(vs) > 0 && !errors.Is(vs, ErrInvalidFleet) {
+				t.Error("Violations do not match ErrInvalidFleet")
+			}
+		})
+	}
+}
@@ -4,13 +4,13 @@ go 1.20
 
 require (
 	github.com/gizak/termui/v3 v3.1.0
+	github.com/google/uuid v1.3.0
+	github.com/grupawp/termloop v0.0.0-20230516071741-9af5ae3e8663
 	github.com/grupawp/warships-gui/v2 v2.1.4
 	github.com/micmonay/keybd_event v1.1.1
 )
 
 require (
-	github.com/google/uuid v1.3.0 // indirect
-	github.com/grupawp/termloop v0.0.0-20230516071741-9af5ae3e8663 // indirect
 	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/mitchellh/go-wordwrap v0.0.0-20150314170334-ad45545899c7 // indirect
 	github.com/nsf/termbox-go v1.1.1 // indirect
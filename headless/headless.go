@@ -0,0 +1,341 @@
+// Package headless plays a game over plain text streams, one command per
+// line, so games can be scripted or played by bots without a terminal UI.
+package headless
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"main/bot"
+	"main/client"
+	"main/game"
+	"main/history"
+	"main/replay"
+)
+
+const (
+	statusEnded = "ended"
+	pollDelay   = 300 * time.Millisecond
+)
+
+// Usage lists the commands understood on stdin.
+const Usage = `commands:
+  fire <coord>   shoot at a cell, e.g. fire B4
+  status         print the game status
+  wait           block until it is our turn or the game ended
+  board          print our fleet, opponent shots and our shots
+  desc           print both players and descriptions
+  abandon        leave the game and quit
+  help           print this text
+  quit           stop reading commands, the game is left running`
+
+// ShotRecord is one of our shots and its result.
+type ShotRecord struct {
+	Coord  string `json:"coord"`
+	Result string `json:"result"`
+}
+
+// Response is printed after every command. Only the fields relevant to the
+// command are set.
+type Response struct {
+	Cmd      string                 `json:"cmd"`
+	Error    string                 `json:"error,omitempty"`
+	Coord    string                 `json:"coord,omitempty"`
+	Result   string                 `json:"result,omitempty"`
+	Status   *client.StatusResponse `json:"status,omitempty"`
+	Desc     *client.GameDesc       `json:"desc,omitempty"`
+	Board    []string               `json:"board,omitempty"`
+	OppShots []string               `json:"opp_shots,omitempty"`
+	Shots    []ShotRecord           `json:"shots,omitempty"`
+	Game     *client.Game           `json:"game,omitempty"`
+}
+
+// Player runs commands against a GameAPI and writes responses as text or
+// JSON lines.
+type Player struct {
+	api   client.GameAPI
+	out   io.Writer
+	json  bool
+	shots []ShotRecord
+	// HistoryDir is where the replay is saved when the game ends, no
+	// replay is written when it is empty.
+	HistoryDir string
+	// MatchesPath is the match history the game is added to when set.
+	MatchesPath string
+	rec         *replay.Replay
+}
+
+func NewPlayer(api client.GameAPI, out io.Writer, jsonLines bool) *Player {
+	return &Player{api: api, out: out, json: jsonLines}
+}
+
+// Run starts the game and executes commands from in until EOF, quit or
+// abandon. Failed commands are reported and do not stop the loop.
+func (p *Player) Run(ctx context.Context, g client.Game, in io.Reader) error {
+	started, err := p.api.InitGame(ctx, g)
+	if err != nil {
+		return fmt.Errorf("headless Run, InitGame; %w", err)
+	}
+	p.write(Response{Cmd: "init", Game: &started})
+	p.rec = replay.New()
+	_, offline := p.api.(*bot.LocalGame)
+	p.rec.Bot = g.WPBot || offline
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		cmd := strings.ToLower(fields[0])
+		if cmd == "quit" || cmd == "exit" {
+			p.finishEnded(ctx)
+			return nil
+		}
+		if cmd == "abandon" {
+			p.snapshot(ctx)
+		}
+		resp := p.exec(ctx, cmd, fields[1:])
+		p.write(resp)
+		if cmd == "abandon" {
+			p.finish(ctx, replay.StatusAbandoned, nil)
+			return nil
+		}
+		if resp.Status != nil && resp.Status.GameStatus == statusEnded {
+			p.finish(ctx, resp.Status.LastGameStatus, resp.Status.OppShots)
+		} else if resp.Result == string(game.ResultSunk) {
+			p.finishEnded(ctx)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	p.finishEnded(ctx)
+	return nil
+}
+
+// finishEnded saves the replay when the server says the game is over,
+// fire responses carry no status so the last shot is not noticed otherwise.
+func (p *Player) finishEnded(ctx context.Context) {
+	if p.rec == nil {
+		return
+	}
+	status, err := p.api.GetStatus(ctx)
+	if err == nil && status.GameStatus == statusEnded {
+		p.finish(ctx, status.LastGameStatus, status.OppShots)
+	}
+}
+
+func (p *Player) exec(ctx context.Context, cmd string, args []string) Response {
+	resp := Response{Cmd: cmd}
+	var err error
+	switch cmd {
+	case "fire", "shoot":
+		err = p.fire(ctx, args, &resp)
+	case "status":
+		var status client.StatusResponse
+		status, err = p.api.GetStatus(ctx)
+		resp.Status = &status
+	case "wait":
+		var status client.StatusResponse
+		status, err = p.wait(ctx)
+		resp.Status = &status
+	case "board":
+		var board client.Board
+		board, err = p.api.GetBoard(ctx)
+		if err != nil {
+			break
+		}
+		var status client.StatusResponse
+		status, err = p.api.GetStatus(ctx)
+		resp.Status = &status
+		resp.Board = board.Board
+		resp.OppShots = status.OppShots
+		resp.Shots = p.shots
+	case "desc":
+		var desc client.GameDesc
+		desc, err = p.api.GetDescription(ctx)
+		resp.Desc = &desc
+	case "abandon":
+		err = p.api.Abandon(ctx)
+	case "help":
+	default:
+		err = fmt.Errorf("unknown command %q, try help", cmd)
+	}
+	if err != nil {
+		return Response{Cmd: cmd, Error: err.Error()}
+	}
+	return resp
+}
+
+func (p *Player) fire(ctx context.Context, args []string, resp *Response) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: fire <coord>")
+	}
+	c, err := game.ParseCoord(strings.ToUpper(args[0]))
+	if err != nil {
+		return err
+	}
+	result, err := p.api.Shoot(ctx, c.String())
+	if err != nil {
+		return err
+	}
+	p.shots = append(p.shots, ShotRecord{Coord: c.String(), Result: result})
+	if p.rec != nil {
+		p.rec.AddShot(c.String(), result)
+	}
+	resp.Coord = c.String()
+	resp.Result = result
+	return nil
+}
+
+// wait polls the status until we should fire or the game is over.
+func (p *Player) wait(ctx context.Context) (client.StatusResponse, error) {
+	for {
+		status, err := p.api.GetStatus(ctx)
+		if err != nil || status.ShouldFire || status.GameStatus == statusEnded {
+			return status, err
+		}
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-time.After(pollDelay):
+		}
+	}
+}
+
+// snapshot copies the players and our fleet into the replay while the
+// game can still be queried.
+func (p *Player) snapshot(ctx context.Context) {
+	if p.rec == nil {
+		return
+	}
+	if desc, err := p.api.GetDescription(ctx); err == nil {
+		p.rec.Nick, p.rec.Desc = desc.Nick, desc.Desc
+		p.rec.Opponent, p.rec.OppDesc = desc.Opponent, desc.OppDesc
+	}
+	if board, err := p.api.GetBoard(ctx); err == nil {
+		p.rec.Fleet = board.Board
+	}
+}
+
+// finish saves the replay of the game once.
+func (p *Player) finish(ctx context.Context, status string, oppShots []string) {
+	r := p.rec
+	if r == nil || p.HistoryDir == "" {
+		return
+	}
+	if r.Nick == "" {
+		p.snapshot(ctx)
+	}
+	p.rec = nil
+	r.Status = status
+	r.Ended = time.Now()
+	if oppShots != nil {
+		r.OppShots = oppShots
+	}
+	path, err := r.Save(p.HistoryDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "headless: %v\n", err)
+	}
+	if p.MatchesPath != "" {
+		if err := history.Append(p.MatchesPath, history.FromReplay(r, path)); err != nil {
+			fmt.Fprintf(os.Stderr, "headless: %v\n", err)
+		}
+	}
+}
+
+func (p *Player) write(resp Response) {
+	if p.json {
+		data, err := json.Marshal(resp)
+		if err != nil {
+			data, _ = json.Marshal(Response{Cmd: resp.Cmd, Error: err.Error()})
+		}
+		fmt.Fprintln(p.out, string(data))
+		return
+	}
+	fmt.Fprintln(p.out, formatText(resp))
+}
+
+func formatText(resp Response) string {
+	if resp.Error != "" {
+		return "error: " + resp.Error
+	}
+	switch resp.Cmd {
+	case "init":
+		if resp.Game.Nick == "" {
+			return "game started"
+		}
+		return fmt.Sprintf("game started as %s", resp.Game.Nick)
+	case "fire", "shoot":
+		return fmt.Sprintf("%s %s", resp.Coord, resp.Result)
+	case "status", "wait":
+		s := resp.Status
+		text := fmt.Sprintf("status=%s should_fire=%t timer=%d opponent=%s opp_shots=%s",
+			s.GameStatus, s.ShouldFire, s.Timer, s.Opponent, strings.Join(s.OppShots, ","))
+		if s.LastGameStatus != "" {
+			text += " last=" + s.LastGameStatus
+		}
+		return text
+	case "board":
+		return formatBoards(resp.Board, resp.OppShots, resp.Shots)
+	case "desc":
+		d := resp.Desc
+		return fmt.Sprintf("%s: %s\n%s: %s", d.Nick, d.Desc, d.Opponent, d.OppDesc)
+	case "abandon":
+		return "abandoned"
+	case "help":
+		return Usage
+	}
+	return "ok"
+}
+
+// formatBoards draws our fleet with the opponent shots next to the board
+// of our shots: # ship, X hit, o miss, . unknown.
+func formatBoards(fleet, oppShots []string, shots []ShotRecord) string {
+	coords, _ := game.ParseCoords(fleet)
+	own := game.NewBoard(coords)
+	for _, s := range oppShots {
+		if c, err := game.ParseCoord(s); err == nil {
+			own.Fire(c)
+		}
+	}
+	target := &game.Board{}
+	for _, s := range shots {
+		c, err := game.ParseCoord(s.Coord)
+		if err != nil {
+			continue
+		}
+		if s.Result == string(game.ResultMiss) {
+			target.Set(c, game.CellMiss)
+		} else {
+			target.Set(c, game.CellHit)
+		}
+	}
+	chars := map[game.Cell]byte{game.CellEmpty: '.', game.CellShip: '#', game.CellHit: 'X', game.CellMiss: 'o'}
+	var sb strings.Builder
+	header := "   A B C D E F G H I J"
+	sb.WriteString(header + "    " + header + "\n")
+	for y := 0; y < game.Size; y++ {
+		var row strings.Builder
+		for _, b := range []*game.Board{own, target} {
+			row.WriteString(fmt.Sprintf("%2d ", y+1))
+			for x := 0; x < game.Size; x++ {
+				row.WriteByte(chars[b.At(game.Coord{X: x, Y: y})])
+				row.WriteByte(' ')
+			}
+			row.WriteString("   ")
+		}
+		sb.WriteString(strings.TrimRight(row.String(), " ") + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
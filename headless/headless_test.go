@@ -0,0 +1,129 @@
+package headless
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"main/client"
+	"main/history"
+	"main/replay"
+)
+
+var testFleet = []string{
+	"A1", "B1", "C1", "D1",
+	"A3", "B3", "C3", "E3", "F3", "G3",
+	"A5", "B5", "D5", "E5", "G5", "H5",
+	"A7", "C7", "E7", "G7",
+}
+
+func TestRun(t *testing.T) {
+	inProgress := client.StatusResponse{GameStatus: "game_in_progress", ShouldFire: true, Opponent: "bob"}
+	won := client.StatusResponse{GameStatus: statusEnded, LastGameStatus: replay.StatusWin, Opponent: "bob"}
+
+	tests := []struct {
+		name     string
+		input    string
+		statuses []client.StatusResponse
+		results  []string
+		out      []string
+		shots    []string
+		saved    string
+		abandon  bool
+	}{
+		{
+			name:     "sunk ends the game",
+			input:    "fire a1\nfire A2\nfire b9\n",
+			statuses: []client.StatusResponse{won},
+			results:  []string{"hit", "miss", "sunk"},
+			out:      []string{"game started as ann", "A1 hit", "A2 miss", "B9 sunk"},
+			shots:    []string{"A1", "A2", "B9"},
+			saved:    replay.StatusWin,
+		},
+		{
+			name:     "game running at eof",
+			input:    "fire J10\nstatus\n",
+			statuses: []client.StatusResponse{inProgress},
+			out:      []string{"game started as ann", "J10 miss", "status=game_in_progress should_fire=true"},
+			shots:    []string{"J10"},
+		},
+		{
+			name:     "ended noticed on quit",
+			input:    "fire C3\nquit\nfire D4\n",
+			statuses: []client.StatusResponse{won},
+			results:  []string{"hit"},
+			out:      []string{"game started as ann", "C3 hit"},
+			shots:    []string{"C3"},
+			saved:    replay.StatusWin,
+		},
+		{
+			name:    "bad commands do not stop the loop",
+			input:   "fire\nfire K1\nfly\nabandon\n",
+			out:     []string{"game started as ann", "error: usage: fire <coord>", "error: ", "error: unknown command \"fly\", try help", "abandoned"},
+			saved:   replay.StatusAbandoned,
+			abandon: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := client.NewFakeClient()
+			fake.PushStatus(tt.statuses...)
+			fake.PushShotResults(tt.results...)
+			dir := t.TempDir()
+			var out strings.Builder
+			p := NewPlayer(fake, &out, false)
+			p.HistoryDir = filepath.Join(dir, "replays")
+			p.MatchesPath = filepath.Join(dir, "matches.json")
+
+			g := client.Game{Nick: "ann", TargetNick: "bob", Coords: testFleet}
+			if err := p.Run(context.Background(), g, strings.NewReader(tt.input)); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if len(lines) != len(tt.out) {
+				t.Fatalf("output %q, want %d lines", lines, len(tt.out))
+			}
+			for i, want := range tt.out {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("line %d = %q, want prefix %q", i, lines[i], want)
+				}
+			}
+			if got := strings.Join(fake.ShotsFired(), ","); got != strings.Join(tt.shots, ",") {
+				t.Errorf("shots fired %s, want %s", got, strings.Join(tt.shots, ","))
+			}
+			if fake.Abandoned != tt.abandon {
+				t.Errorf("abandoned = %t, want %t", fake.Abandoned, tt.abandon)
+			}
+
+			paths, err := replay.List(p.HistoryDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.saved == "" {
+				if len(paths) != 0 {
+					t.Errorf("replays %v saved for a running game", paths)
+				}
+				return
+			}
+			if len(paths) != 1 {
+				t.Fatalf("replays %v, want one", paths)
+			}
+			r, err := replay.Load(paths[0])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Status != tt.saved || r.Opponent != "bob" || len(r.Shots) != len(tt.shots) {
+				t.Errorf("replay status %s opponent %s shots %d, want %s bob %d", r.Status, r.Opponent, len(r.Shots), tt.saved, len(tt.shots))
+			}
+			store, err := history.Load(p.MatchesPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(store.Matches) != 1 || store.Matches[0].Result != tt.saved {
+				t.Errorf("history %+v, want one %s match", store.Matches, tt.saved)
+			}
+		})
+	}
+}
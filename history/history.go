@@ -0,0 +1,113 @@
+// Package history keeps a summary of every game we played in a local
+// file and computes personal statistics from it.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"main/replay"
+)
+
+const fileName = "matches.json"
+
+// Match is the summary of one finished game.
+type Match struct {
+	Opponent string        `json:"opponent"`
+	Result   string        `json:"result"`
+	Shots    int           `json:"shots"`
+	Hits     int           `json:"hits"`
+	Duration time.Duration `json:"duration"`
+	Bot      bool          `json:"bot"`
+	Played   time.Time     `json:"played"`
+	Replay   string        `json:"replay,omitempty"`
+}
+
+// Accuracy is the share of our shots that hit, 0 without shots.
+func (m Match) Accuracy() float64 {
+	if m.Shots == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(m.Shots)
+}
+
+func (m Match) Won() bool {
+	return m.Result == replay.StatusWin
+}
+
+// FromReplay summarizes a recorded game saved at path.
+func FromReplay(r *replay.Replay, path string) Match {
+	return Match{
+		Opponent: r.Opponent,
+		Result:   r.Status,
+		Shots:    len(r.Shots),
+		Hits:     r.Hits(),
+		Duration: r.Duration(),
+		Bot:      r.Bot,
+		Played:   r.Started,
+		Replay:   path,
+	}
+}
+
+// Store is the list of matches backed by one file, oldest first.
+type Store struct {
+	path    string
+	Matches []Match `json:"matches"`
+}
+
+// DefaultPath returns matches.json in the statki user config dir.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("history DefaultPath: os.UserConfigDir: %w", err)
+	}
+	return filepath.Join(dir, "statki", fileName), nil
+}
+
+// Load reads the store at path. A missing file is an empty history.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history Load: os.ReadFile: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("history Load: json.Unmarshal: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("history Save: os.MkdirAll: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("history Save: json.Marshal: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("history Save: os.WriteFile: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("history Save: os.Rename: %w", err)
+	}
+	return nil
+}
+
+// Append loads the store at path, adds m and saves it.
+func Append(path string, m Match) error {
+	s, err := Load(path)
+	if err != nil {
+		return err
+	}
+	s.Matches = append(s.Matches, m)
+	return s.Save()
+}
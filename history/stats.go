@@ -0,0 +1,138 @@
+package history
+
+import (
+	"sort"
+	"time"
+
+	"main/replay"
+)
+
+// OpponentStats are our results against one opponent.
+type OpponentStats struct {
+	Opponent string
+	Games    int
+	Wins     int
+	// Bot is true when every game against the opponent was a bot game.
+	Bot bool
+}
+
+func (o OpponentStats) WinRate() float64 {
+	if o.Games == 0 {
+		return 0
+	}
+	return float64(o.Wins) / float64(o.Games)
+}
+
+// Summary are the personal statistics over a list of matches. Abandoned
+// games count as played but neither won nor lost.
+type Summary struct {
+	Games         int
+	Wins          int
+	Losses        int
+	Abandoned     int
+	Shots         int
+	Hits          int
+	AvgDuration   time.Duration
+	AvgShots      float64
+	LongestStreak int
+	CurrentStreak int
+	ByOpponent    []OpponentStats
+	// Accuracy is the accuracy of every game in the order played.
+	Accuracy []float64
+}
+
+// WinRate is wins over decided games.
+func (s Summary) WinRate() float64 {
+	if s.Wins+s.Losses == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Wins+s.Losses)
+}
+
+func (s Summary) TotalAccuracy() float64 {
+	if s.Shots == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Shots)
+}
+
+// Summarize computes the statistics of matches, sorted by play time.
+func Summarize(matches []Match) Summary {
+	sorted := append([]Match(nil), matches...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Played.Before(sorted[j].Played)
+	})
+
+	var s Summary
+	var total time.Duration
+	byOpp := make(map[string]*OpponentStats)
+	streak := 0
+	for _, m := range sorted {
+		s.Games++
+		s.Shots += m.Shots
+		s.Hits += m.Hits
+		total += m.Duration
+		s.Accuracy = append(s.Accuracy, m.Accuracy())
+
+		o, ok := byOpp[m.Opponent]
+		if !ok {
+			o = &OpponentStats{Opponent: m.Opponent, Bot: m.Bot}
+			byOpp[m.Opponent] = o
+		}
+		o.Bot = o.Bot && m.Bot
+		o.Games++
+
+		switch {
+		case m.Won():
+			s.Wins++
+			o.Wins++
+			streak++
+			if streak > s.LongestStreak {
+				s.LongestStreak = streak
+			}
+		case m.Result == replay.StatusLose:
+			s.Losses++
+			streak = 0
+		default:
+			s.Abandoned++
+			streak = 0
+		}
+	}
+	s.CurrentStreak = streak
+	if s.Games > 0 {
+		s.AvgDuration = total / time.Duration(s.Games)
+		s.AvgShots = float64(s.Shots) / float64(s.Games)
+	}
+	for _, o := range byOpp {
+		s.ByOpponent = append(s.ByOpponent, *o)
+	}
+	sort.Slice(s.ByOpponent, func(i, j int) bool {
+		a, b := s.ByOpponent[i], s.ByOpponent[j]
+		if a.Games != b.Games {
+			return a.Games > b.Games
+		}
+		return a.Opponent < b.Opponent
+	})
+	return s
+}
+
+// MovingAverage smooths values over a window of the last n entries.
+func MovingAverage(values []float64, n int) []float64 {
+	if n < 1 {
+		n = 1
+	}
+	res := make([]float64, len(values))
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= n {
+			sum -= values[i-n]
+		}
+		size := i + 1
+		if size > n {
+			size = n
+		}
+		res[i] = sum / float64(size)
+	}
+	return res
+}
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"main/replay"
+)
+
+func TestSummarize(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	match := func(hour int, opp, result string, shots, hits int) Match {
+		return Match{Opponent: opp, Result: result, Shots: shots, Hits: hits, Duration: time.Minute, Played: day.Add(time.Duration(hour) * time.Hour)}
+	}
+	botMatch := func(m Match) Match {
+		m.Bot = true
+		return m
+	}
+	tests := []struct {
+		name    string
+		matches []Match
+		want    Summary
+	}{
+		{name: "empty", want: Summary{}},
+		{
+			name: "streaks follow play time, not input order",
+			matches: []Match{
+				match(3, "bob", replay.StatusWin, 40, 20),
+				match(1, "bob", replay.StatusWin, 50, 20),
+				match(2, "ann", replay.StatusLose, 60, 15),
+				match(4, "ann", replay.StatusWin, 30, 20),
+				match(5, "bob", replay.StatusAbandoned, 20, 5),
+				match(6, "bob", replay.StatusWin, 50, 20),
+			},
+			want: Summary{
+				Games: 6, Wins: 4, Losses: 1, Abandoned: 1,
+				Shots: 250, Hits: 100,
+				AvgDuration: time.Minute, AvgShots: 250.0 / 6,
+				LongestStreak: 2, CurrentStreak: 1,
+				ByOpponent: []OpponentStats{
+					{Opponent: "bob", Games: 4, Wins: 3},
+					{Opponent: "ann", Games: 2, Wins: 1},
+				},
+				Accuracy: []float64{0.4, 0.25, 0.5, 2.0 / 3, 0.25, 0.4},
+			},
+		},
+		{
+			name: "bot only when every game against the nick was",
+			matches: []Match{
+				botMatch(match(1, "bot", replay.StatusWin, 40, 20)),
+				botMatch(match(2, "bob", replay.StatusWin, 40, 20)),
+				match(3, "bob", replay.StatusLose, 40, 20),
+				match(4, "ann", replay.StatusLose, 40, 20),
+				botMatch(match(5, "ann", replay.StatusLose, 40, 20)),
+			},
+			want: Summary{
+				Games: 5, Wins: 2, Losses: 3,
+				Shots: 200, Hits: 100,
+				AvgDuration: time.Minute, AvgShots: 40,
+				LongestStreak: 2,
+				ByOpponent: []OpponentStats{
+					{Opponent: "ann", Games: 2},
+					{Opponent: "bob", Games: 2, Wins: 1},
+					{Opponent: "bot", Games: 1, Wins: 1, Bot: true},
+				},
+				Accuracy: []float64{0.5, 0.5, 0.5, 0.5, 0.5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Summarize(tt.matches)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Summarize() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummaryRates(t *testing.T) {
+	s := Summary{Wins: 3, Losses: 1, Abandoned: 4, Shots: 80, Hits: 20}
+	if got := s.WinRate(); got != 0.75 {
+		t.Errorf("WinRate() = %v, want 0.75, abandoned games are not decided", got)
+	}
+	if got := s.TotalAccuracy(); got != 0.25 {
+		t.Errorf("TotalAccuracy() = %v, want 0.25", got)
+	}
+}
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		values []float64
+		n      int
+		want   []float64
+	}{
+		{values: nil, n: 3, want: []float64{}},
+		{values: []float64{1, 2, 3, 4}, n: 2, want: []float64{1, 1.5, 2.5, 3.5}},
+		{values: []float64{2, 4}, n: 0, want: []float64{2, 4}},
+	}
+	for _, tt := range tests {
+		if got := MovingAverage(tt.values, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MovingAverage(%v, %d) = %v, want %v", tt.values, tt.n, got, tt.want)
+		}
+	}
+}
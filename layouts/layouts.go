@@ -0,0 +1,169 @@
+// Package layouts stores named fleet layouts in a local JSON file and
+// converts them to and from a shareable text form.
+package layouts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"main/game"
+)
+
+const fileName = "layouts.json"
+
+var ErrNotFound = errors.New("layout not found")
+
+// Layout is a named, valid fleet.
+type Layout struct {
+	Name   string    `json:"name"`
+	Coords []string  `json:"coords"`
+	Saved  time.Time `json:"saved"`
+}
+
+// Library is the set of saved layouts backed by one file.
+type Library struct {
+	path    string
+	Layouts []Layout `json:"layouts"`
+}
+
+// DefaultPath returns layouts.json in the statki user config dir.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("layouts DefaultPath: os.UserConfigDir: %w", err)
+	}
+	return filepath.Join(dir, "statki", fileName), nil
+}
+
+// Load reads the library at path. A missing file is an empty library.
+func Load(path string) (*Library, error) {
+	lib := &Library{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lib, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("layouts Load: os.ReadFile: %w", err)
+	}
+	if err := json.Unmarshal(data, lib); err != nil {
+		return nil, fmt.Errorf("layouts Load: json.Unmarshal: %w", err)
+	}
+	return lib, nil
+}
+
+// LoadDefault loads the library at DefaultPath.
+func LoadDefault() (*Library, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Save writes the library through a temp file so a failed write leaves
+// the previous file intact.
+func (l *Library) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("layouts Save: os.MkdirAll: %w", err)
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("layouts Save: json.Marshal: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("layouts Save: os.WriteFile: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("layouts Save: os.Rename: %w", err)
+	}
+	return nil
+}
+
+// Put validates the fleet and stores it under name, replacing any layout
+// with the same name.
+func (l *Library) Put(name string, coords []string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("layouts Put: empty name")
+	}
+	parsed, err := game.ParseCoords(coords)
+	if err != nil {
+		return fmt.Errorf("layouts Put: %w", err)
+	}
+	if vs := game.ValidateLayout(parsed); len(vs) > 0 {
+		return fmt.Errorf("layouts Put: %w", vs)
+	}
+	layout := Layout{Name: name, Coords: game.FormatCoords(parsed), Saved: time.Now()}
+	for i := range l.Layouts {
+		if l.Layouts[i].Name == name {
+			l.Layouts[i] = layout
+			return nil
+		}
+	}
+	l.Layouts = append(l.Layouts, layout)
+	return nil
+}
+
+func (l *Library) Get(name string) (Layout, error) {
+	for _, layout := range l.Layouts {
+		if layout.Name == name {
+			return layout, nil
+		}
+	}
+	return Layout{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+}
+
+func (l *Library) Delete(name string) error {
+	for i, layout := range l.Layouts {
+		if layout.Name == name {
+			l.Layouts = append(l.Layouts[:i], l.Layouts[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrNotFound, name)
+}
+
+// Names returns the layout names in alphabetical order.
+func (l *Library) Names() []string {
+	names := make([]string, 0, len(l.Layouts))
+	for _, layout := range l.Layouts {
+		names = append(names, layout.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Encode renders a layout as a single shareable line: "name: A1 A2 ...".
+func Encode(layout Layout) string {
+	return layout.Name + ": " + strings.Join(layout.Coords, " ")
+}
+
+// Decode parses text produced by Encode. The name part is optional and
+// coordinates may be separated by spaces, commas or new lines.
+func Decode(text string) (Layout, error) {
+	text = strings.TrimSpace(text)
+	var layout Layout
+	if i := strings.Index(text, ":"); i >= 0 {
+		layout.Name = strings.TrimSpace(text[:i])
+		text = text[i+1:]
+	}
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '\n' || r == '\r' || r == '\t' || r == ';'
+	})
+	parsed, err := game.ParseCoords(fields)
+	if err != nil {
+		return Layout{}, fmt.Errorf("layouts Decode: %w", err)
+	}
+	if vs := game.ValidateLayout(parsed); len(vs) > 0 {
+		return Layout{}, fmt.Errorf("layouts Decode: %w", vs)
+	}
+	layout.Coords = game.FormatCoords(parsed)
+	return layout, nil
+}
@@ -0,0 +1,72 @@
+package layouts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var fleet = []string{
+	"A1", "B1", "C1", "D1",
+	"A3", "B3", "C3", "E3", "F3", "G3",
+	"A5", "B5", "D5", "E5", "G5", "H5",
+	"A7", "C7", "E7", "G7",
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statki", fileName)
+	lib, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lib.Put("corners", fleet); err != nil {
+		t.Fatal(err)
+	}
+	if err := lib.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temp file left behind: %v", err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := loaded.Get("corners")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Coords, fleet) {
+		t.Errorf("loaded %v, want %v", got.Coords, fleet)
+	}
+}
+
+func TestSaveKeepsOldFileOnError(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, fileName)
+	lib, _ := Load(path)
+	if err := lib.Put("first", fleet); err != nil {
+		t.Fatal(err)
+	}
+	if err := lib.Save(); err != nil {
+		t.Fatal(err)
+	}
+	// a directory in place of the temp file makes the write fail
+	if err := os.Mkdir(path+".tmp", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := lib.Put("second", fleet); err != nil {
+		t.Fatal(err)
+	}
+	if err := lib.Save(); err == nil {
+		t.Fatal("Save succeeded, want an error")
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("library corrupted: %v", err)
+	}
+	if names := loaded.Names(); !reflect.DeepEqual(names, []string{"first"}) {
+		t.Errorf("names %v, want [first]", names)
+	}
+}
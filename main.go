@@ -1,10 +1,295 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"runtime"
+	"strings"
+	"time"
+
 	"main/app"
+	"main/bot"
+	"main/client"
+	"main/headless"
+	"main/history"
+	"main/layouts"
+	"main/replay"
+	"main/server"
+	"main/tournament"
 )
 
 func main() {
-	game := app.App{}
-	game.Start()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			serve(os.Args[2:])
+			return
+		case "layouts":
+			layoutsCmd(os.Args[2:])
+			return
+		case "play":
+			play(os.Args[2:])
+			return
+		case "tournament":
+			tournamentCmd(os.Args[2:])
+			return
+		case "replay":
+			replayCmd(os.Args[2:])
+			return
+		}
+	}
+	fs := flag.NewFlagSet("statki", flag.ExitOnError)
+	cfg := registerClientFlags(fs)
+	appCfg := registerAppFlags(fs)
+	_ = fs.Parse(os.Args[1:])
+
+	appCfg.newApp(cfg).Start()
+}
+
+// serve runs the local reference server: statki serve [-addr :8080]
+func serve(args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	turn := fs.Duration("turn-timeout", 0, "time a player has to fire (default 60s)")
+	seed := fs.Int64("seed", 0, "random seed for bot fleets and shots (0 = time based)")
+	_ = fs.Parse(args)
+
+	srv := server.New(server.Config{TurnTimeout: *turn, Seed: *seed})
+	fmt.Printf("statki server listening on http://%s/api\n", *addr)
+	if err := srv.ListenAndServe(*addr); err != nil {
+		log.Fatalf("serve: %v", err)
+	}
+}
+
+// play starts a game, in the terminal UI or with -headless reading
+// commands from stdin: statki play -headless [-format json] [-bot]
+func play(args []string) {
+	fs := flag.NewFlagSet("play", flag.ExitOnError)
+	cfg := registerClientFlags(fs)
+	appCfg := registerAppFlags(fs)
+	headlessMode := fs.Bool("headless", false, "read commands from stdin instead of showing the UI")
+	format := fs.String("format", "text", "headless output format: text or json")
+	nick := fs.String("nick", "", "player nick")
+	desc := fs.String("desc", "", "player description")
+	wpbot := fs.Bool("bot", false, "play against the server bot")
+	target := fs.String("target", "", "nick of the player to challenge")
+	coords := fs.String("coords", "", "fleet coordinates separated by spaces or commas (default: server picks)")
+	layout := fs.String("layout", "", "name of a saved fleet layout")
+	offline := fs.String("offline", "", "play offline against the local bot: easy, medium or hard")
+	_ = fs.Parse(args)
+
+	if !*headlessMode {
+		appCfg.newApp(cfg).Start()
+		return
+	}
+	if *format != "text" && *format != "json" {
+		log.Fatalf("play: unknown format %q", *format)
+	}
+	g := client.Game{Nick: *nick, Desc: *desc, WPBot: *wpbot, TargetNick: *target}
+	if *coords != "" {
+		g.Coords = strings.FieldsFunc(strings.ToUpper(*coords), func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+	if *layout != "" {
+		lib, err := layouts.LoadDefault()
+		if err != nil {
+			log.Fatalf("play: %v", err)
+		}
+		saved, err := lib.Get(*layout)
+		if err != nil {
+			log.Fatalf("play: %v", err)
+		}
+		g.Coords = saved.Coords
+	}
+	api := cfg.newAPI()
+	if *offline != "" {
+		difficulty, err := bot.ParseDifficulty(*offline)
+		if err != nil {
+			log.Fatalf("play: %v", err)
+		}
+		api = bot.NewLocalGame(bot.LocalOptions{Difficulty: difficulty})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	player := headless.NewPlayer(api, os.Stdout, *format == "json")
+	if dir, err := replay.DefaultDir(); err == nil {
+		player.HistoryDir = dir
+	}
+	if path, err := history.DefaultPath(); err == nil {
+		player.MatchesPath = path
+	}
+	if err := player.Run(ctx, g, os.Stdin); err != nil {
+		log.Fatalf("play: %v", err)
+	}
+}
+
+// tournamentCmd plays bot strategies against each other and prints the
+// results: statki tournament [-games 100] [-strategies a,b] [-csv out.csv]
+func tournamentCmd(args []string) {
+	fs := flag.NewFlagSet("tournament", flag.ExitOnError)
+	cfg := registerClientFlags(fs)
+	games := fs.Int("games", 100, "games per pair of strategies")
+	workers := fs.Int("workers", runtime.NumCPU(), "games played in parallel")
+	seed := fs.Int64("seed", 0, "random seed (0 = time based)")
+	names := fs.String("strategies", strings.Join(bot.Strategies(), ","), "comma separated strategies, one of "+strings.Join(bot.Strategies(), ", "))
+	engine := fs.String("engine", "local", "where games are played: local or server (uses -url)")
+	csvPath := fs.String("csv", "", "write the results table as CSV to this file")
+	heatPath := fs.String("heatmap-csv", "", "write the shot heatmaps as CSV to this file")
+	heatmaps := fs.Bool("heatmaps", true, "print shot heatmaps")
+	_ = fs.Parse(args)
+
+	tcfg := tournament.Config{
+		Strategies: strings.Split(*names, ","),
+		Games:      *games,
+		Workers:    *workers,
+		Seed:       *seed,
+	}
+	switch *engine {
+	case "local":
+		tcfg.Engine = tournament.LocalEngine{}
+	case "server":
+		tcfg.Engine = tournament.ServerEngine{NewAPI: cfg.newAPI}
+	default:
+		log.Fatalf("tournament: unknown engine %q", *engine)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	report, runErr := tournament.Run(ctx, tcfg)
+	if report == nil {
+		log.Fatalf("tournament: %v", runErr)
+	}
+	if err := report.WriteTable(os.Stdout); err != nil {
+		log.Fatalf("tournament: %v", err)
+	}
+	if *heatmaps {
+		fmt.Println()
+		if err := report.WriteHeatmaps(os.Stdout); err != nil {
+			log.Fatalf("tournament: %v", err)
+		}
+	}
+	writeFile := func(path string, write func(io.Writer) error) {
+		if path == "" {
+			return
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			log.Fatalf("tournament: %v", err)
+		}
+		defer f.Close()
+		if err := write(f); err != nil {
+			log.Fatalf("tournament: %v", err)
+		}
+	}
+	writeFile(*csvPath, report.WriteCSV)
+	writeFile(*heatPath, report.WriteHeatmapCSV)
+	if runErr != nil {
+		log.Fatalf("tournament: stopped early, the results above are partial: %v", runErr)
+	}
+}
+
+// replayCmd plays back a recorded game: statki replay [-speed 1s] <file>
+// Without a file it lists the recorded games.
+func replayCmd(args []string) {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := fs.Duration("speed", time.Second, "delay between shots during playback")
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		dir, err := replay.DefaultDir()
+		if err != nil {
+			log.Fatalf("replay: %v", err)
+		}
+		paths, err := replay.List(dir)
+		if err != nil {
+			log.Fatalf("replay: %v", err)
+		}
+		if len(paths) == 0 {
+			fmt.Printf("no replays in %s\n", dir)
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return
+	}
+	r, err := replay.Load(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("replay: %v", err)
+	}
+	viewer := app.App{}
+	if err := viewer.ViewReplay(r, *speed); err != nil {
+		log.Fatalf("replay: %v", err)
+	}
+}
+
+// layoutsCmd manages saved fleet layouts:
+//
+//	statki layouts list
+//	statki layouts export <name>
+//	statki layouts import [name] < layout.txt
+//	statki layouts delete <name>
+func layoutsCmd(args []string) {
+	if len(args) == 0 {
+		log.Fatalf("usage: statki layouts list | export <name> | import [name] | delete <name>")
+	}
+	lib, err := layouts.LoadDefault()
+	if err != nil {
+		log.Fatalf("layouts: %v", err)
+	}
+	switch args[0] {
+	case "list":
+		for _, name := range lib.Names() {
+			fmt.Println(name)
+		}
+	case "export":
+		if len(args) < 2 {
+			log.Fatalf("usage: statki layouts export <name>")
+		}
+		layout, err := lib.Get(args[1])
+		if err != nil {
+			log.Fatalf("layouts export: %v", err)
+		}
+		fmt.Println(layouts.Encode(layout))
+	case "import":
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			log.Fatalf("layouts import: %v", err)
+		}
+		layout, err := layouts.Decode(string(data))
+		if err != nil {
+			log.Fatalf("layouts import: %v", err)
+		}
+		if len(args) > 1 {
+			layout.Name = args[1]
+		}
+		if layout.Name == "" {
+			log.Fatalf("layouts import: no name given and none in the input")
+		}
+		if err := lib.Put(layout.Name, layout.Coords); err != nil {
+			log.Fatalf("layouts import: %v", err)
+		}
+		if err := lib.Save(); err != nil {
+			log.Fatalf("layouts import: %v", err)
+		}
+		fmt.Printf("imported %s\n", layout.Name)
+	case "delete":
+		if len(args) < 2 {
+			log.Fatalf("usage: statki layouts delete <name>")
+		}
+		if err := lib.Delete(args[1]); err != nil {
+			log.Fatalf("layouts delete: %v", err)
+		}
+		if err := lib.Save(); err != nil {
+			log.Fatalf("layouts delete: %v", err)
+		}
+	default:
+		log.Fatalf("layouts: unknown command %q", args[0])
+	}
 }
@@ -0,0 +1,152 @@
+// Package replay records finished games as versioned JSON files so they
+// can be reviewed later.
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Version is written to every replay. Load rejects newer versions.
+const Version = 1
+
+const (
+	dirName = "history"
+	ext     = ".json"
+
+	StatusWin       = "win"
+	StatusLose      = "lose"
+	StatusAbandoned = "abandoned"
+)
+
+var ErrVersion = errors.New("unsupported replay version")
+
+// Shot is one of our shots with the result the server gave.
+type Shot struct {
+	Coord  string    `json:"coord"`
+	Result string    `json:"result"`
+	Time   time.Time `json:"time"`
+}
+
+// Replay is everything needed to play a game back. OppShots are the
+// opponent shots in the order the server reported them.
+type Replay struct {
+	Version  int       `json:"version"`
+	Nick     string    `json:"nick"`
+	Desc     string    `json:"desc"`
+	Opponent string    `json:"opponent"`
+	OppDesc  string    `json:"opp_desc"`
+	Bot      bool      `json:"bot"`
+	Fleet    []string  `json:"fleet"`
+	Shots    []Shot    `json:"shots"`
+	OppShots []string  `json:"opp_shots"`
+	Status   string    `json:"status"`
+	Started  time.Time `json:"started"`
+	Ended    time.Time `json:"ended"`
+}
+
+// New starts a replay of a game beginning now.
+func New() *Replay {
+	return &Replay{Version: Version, Started: time.Now()}
+}
+
+// AddShot appends our shot fired now.
+func (r *Replay) AddShot(coord, result string) {
+	r.Shots = append(r.Shots, Shot{Coord: coord, Result: result, Time: time.Now()})
+}
+
+// Duration is how long the game took.
+func (r *Replay) Duration() time.Duration {
+	if r.Ended.IsZero() {
+		return 0
+	}
+	return r.Ended.Sub(r.Started)
+}
+
+// Hits counts our shots that hit or sank a ship.
+func (r *Replay) Hits() int {
+	n := 0
+	for _, s := range r.Shots {
+		if s.Result == "hit" || s.Result == "sunk" {
+			n++
+		}
+	}
+	return n
+}
+
+// DefaultDir returns the history dir under the statki user config dir.
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("replay DefaultDir: os.UserConfigDir: %w", err)
+	}
+	return filepath.Join(dir, "statki", dirName), nil
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// FileName is the name a replay is saved under: the start time followed
+// by both nicks.
+func (r *Replay) FileName() string {
+	name := fmt.Sprintf("%s_%s_vs_%s", r.Started.Format("20060102-150405"), r.Nick, r.Opponent)
+	return unsafeChars.ReplaceAllString(name, "_") + ext
+}
+
+// Save writes the replay into dir and returns the file path.
+func (r *Replay) Save(dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("replay Save: os.MkdirAll: %w", err)
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("replay Save: json.Marshal: %w", err)
+	}
+	path := filepath.Join(dir, r.FileName())
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("replay Save: os.WriteFile: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads a replay file.
+func Load(path string) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay Load: os.ReadFile: %w", err)
+	}
+	var r Replay
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("replay Load: json.Unmarshal: %w", err)
+	}
+	if r.Version < 1 || r.Version > Version {
+		return nil, fmt.Errorf("replay Load: %w: %d", ErrVersion, r.Version)
+	}
+	return &r, nil
+}
+
+// List returns the replay files in dir, oldest first. A missing dir has
+// no replays.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("replay List: os.ReadDir: %w", err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ext) {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
@@ -0,0 +1,95 @@
+package replay
+
+import "main/game"
+
+// Step is a single shot of the game, ours or the opponent's.
+type Step struct {
+	Ours   bool
+	Coord  string
+	Result string
+}
+
+// Steps interleaves our shots and the opponent shots in the order they
+// were fired. Only our shots carry times, so the order is rebuilt from
+// the rules: a turn lasts until a miss and turns alternate. The side with
+// more turns started; with equal counts the loser did, as the game ends
+// during the winner's turn.
+func (r *Replay) Steps() []Step {
+	ours := splitTurns(r.ourSteps())
+	theirs := splitTurns(r.oppSteps())
+
+	weStart := len(ours) > len(theirs)
+	if len(ours) == len(theirs) {
+		weStart = r.Status != StatusWin
+	}
+	var steps []Step
+	for i := 0; i < len(ours) || i < len(theirs); i++ {
+		first, second := theirs, ours
+		if weStart {
+			first, second = ours, theirs
+		}
+		if i < len(first) {
+			steps = append(steps, first[i]...)
+		}
+		if i < len(second) {
+			steps = append(steps, second[i]...)
+		}
+	}
+	return steps
+}
+
+// TurnStarts returns the index of the first step of every turn, a turn
+// being a run of consecutive shots by the same side.
+func TurnStarts(steps []Step) []int {
+	var starts []int
+	for i, s := range steps {
+		if i == 0 || s.Ours != steps[i-1].Ours {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+func (r *Replay) ourSteps() []Step {
+	steps := make([]Step, 0, len(r.Shots))
+	for _, s := range r.Shots {
+		steps = append(steps, Step{Ours: true, Coord: s.Coord, Result: s.Result})
+	}
+	return steps
+}
+
+// oppSteps works out the result of every opponent shot from our fleet.
+func (r *Replay) oppSteps() []Step {
+	coords, _ := game.ParseCoords(r.Fleet)
+	board := game.NewBoard(coords)
+	steps := make([]Step, 0, len(r.OppShots))
+	for _, s := range r.OppShots {
+		c, err := game.ParseCoord(s)
+		if err != nil {
+			continue
+		}
+		result, err := board.Fire(c)
+		if err != nil {
+			continue
+		}
+		steps = append(steps, Step{Coord: s, Result: string(result)})
+	}
+	return steps
+}
+
+// splitTurns groups steps into turns, each ending with a miss.
+func splitTurns(steps []Step) [][]Step {
+	var turns [][]Step
+	var turn []Step
+	for _, s := range steps {
+		turn = append(turn, s)
+		if s.Result == string(game.ResultMiss) {
+			turns = append(turns, turn)
+			turn = nil
+		}
+	}
+	if len(turn) > 0 {
+		turns = append(turns, turn)
+	}
+	return turns
+}
@@ -0,0 +1,87 @@
+package replay
+
+import (
+	"reflect"
+	"testing"
+)
+
+func shots(pairs ...string) []Shot {
+	var res []Shot
+	for i := 0; i < len(pairs); i += 2 {
+		res = append(res, Shot{Coord: pairs[i], Result: pairs[i+1]})
+	}
+	return res
+}
+
+func TestSteps(t *testing.T) {
+	fleet := []string{"A1", "B1", "D4"}
+	tests := []struct {
+		name string
+		r    Replay
+		want []Step
+	}{
+		{
+			name: "more turns of ours, we started",
+			r: Replay{Fleet: fleet, Status: StatusLose,
+				Shots:    shots("J1", "miss", "J2", "hit", "J3", "miss"),
+				OppShots: []string{"A1", "C3"}},
+			want: []Step{
+				{Ours: true, Coord: "J1", Result: "miss"},
+				{Coord: "A1", Result: "hit"},
+				{Coord: "C3", Result: "miss"},
+				{Ours: true, Coord: "J2", Result: "hit"},
+				{Ours: true, Coord: "J3", Result: "miss"},
+			},
+		},
+		{
+			name: "equal turns, the winner went second",
+			r: Replay{Fleet: fleet, Status: StatusWin,
+				Shots:    shots("J1", "hit", "J2", "sunk"),
+				OppShots: []string{"C3"}},
+			want: []Step{
+				{Coord: "C3", Result: "miss"},
+				{Ours: true, Coord: "J1", Result: "hit"},
+				{Ours: true, Coord: "J2", Result: "sunk"},
+			},
+		},
+		{
+			name: "opponent results come from our fleet, repeats are dropped",
+			r: Replay{Fleet: fleet, Status: StatusLose,
+				Shots:    shots("J1", "miss"),
+				OppShots: []string{"A1", "A1", "B1", "D4"}},
+			want: []Step{
+				{Ours: true, Coord: "J1", Result: "miss"},
+				{Coord: "A1", Result: "hit"},
+				{Coord: "B1", Result: "sunk"},
+				{Coord: "D4", Result: "sunk"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Steps(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Steps() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTurnStarts(t *testing.T) {
+	tests := []struct {
+		ours []bool
+		want []int
+	}{
+		{ours: nil, want: nil},
+		{ours: []bool{true}, want: []int{0}},
+		{ours: []bool{true, true, false, true, true, true, false}, want: []int{0, 2, 3, 6}},
+	}
+	for _, tt := range tests {
+		steps := make([]Step, len(tt.ours))
+		for i, o := range tt.ours {
+			steps[i].Ours = o
+		}
+		if got := TurnStarts(steps); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("TurnStarts(%v) = %v, want %v", tt.ours, got, tt.want)
+		}
+	}
+}
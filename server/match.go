@@ -0,0 +1,122 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"main/client"
+	"main/game"
+)
+
+const (
+	statusWaiting        = "waiting"
+	statusWaitingWPBot   = "waiting_wpbot"
+	statusGameInProgress = "game_in_progress"
+	statusEnded          = "ended"
+
+	lastWin  = "win"
+	lastLose = "lose"
+
+	botNick = "WP_Bot"
+	botDesc = "Local reference bot"
+)
+
+var errAlreadyFired = errors.New("already fired at")
+
+// side is one half of a match, either a registered player or the bot.
+type side struct {
+	player *player
+	nick   string
+	desc   string
+	board  *game.Board
+	shots  []string
+	fired  map[game.Coord]bool
+}
+
+func newSide(p *player, nick, desc string, coords []game.Coord) *side {
+	return &side{
+		player: p,
+		nick:   nick,
+		desc:   desc,
+		board:  game.NewBoard(coords),
+		fired:  make(map[game.Coord]bool),
+	}
+}
+
+func (s *side) isBot() bool {
+	return s.player == nil
+}
+
+// match is a single game between two sides. turn is the index of the
+// side that should fire next.
+type match struct {
+	sides    [2]*side
+	turn     int
+	status   string
+	startAt  time.Time
+	deadline time.Time
+	winner   int
+}
+
+func (m *match) opponent(i int) int {
+	return 1 - i
+}
+
+func (m *match) timer(now time.Time) int {
+	if m.status != statusGameInProgress {
+		return 0
+	}
+	left := m.deadline.Sub(now)
+	if left < 0 {
+		return 0
+	}
+	return int(left.Round(time.Second) / time.Second)
+}
+
+func (m *match) end(winner int) {
+	m.status = statusEnded
+	m.winner = winner
+}
+
+// fire applies a shot of side i and returns its result. It does not
+// check whose turn it is, but rejects cells the side already fired at.
+func (m *match) fire(i int, coord game.Coord) (game.Result, error) {
+	shooter := m.sides[i]
+	target := m.sides[m.opponent(i)]
+	if shooter.fired[coord] {
+		return "", errAlreadyFired
+	}
+	result, err := target.board.Fire(coord)
+	if err != nil {
+		return "", err
+	}
+	shooter.shots = append(shooter.shots, coord.String())
+	shooter.fired[coord] = true
+	if result == game.ResultMiss {
+		m.turn = m.opponent(i)
+	} else if target.board.Defeated() {
+		m.end(i)
+	}
+	return result, nil
+}
+
+func (m *match) statusFor(i int, now time.Time) client.StatusResponse {
+	opp := m.sides[m.opponent(i)]
+	resp := client.StatusResponse{
+		GameStatus: m.status,
+		Nick:       m.sides[i].nick,
+		Opponent:   opp.nick,
+		OppShots:   append([]string{}, opp.shots...),
+	}
+	switch m.status {
+	case statusGameInProgress:
+		resp.ShouldFire = m.turn == i
+		resp.Timer = m.timer(now)
+	case statusEnded:
+		resp.LastGameStatus = lastLose
+		if m.winner == i {
+			resp.LastGameStatus = lastWin
+		}
+	}
+	return resp
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"testing"
+
+	"main/game"
+)
+
+func TestMatchFire(t *testing.T) {
+	// side 1 has a two cell ship at A1-B1, side 0 a single cell at J10
+	m := &match{
+		sides: [2]*side{
+			newSide(nil, "a", "", []game.Coord{{X: 9, Y: 9}}),
+			newSide(nil, "b", "", []game.Coord{{X: 0, Y: 0}, {X: 1, Y: 0}}),
+		},
+		status: statusGameInProgress,
+	}
+	steps := []struct {
+		side   int
+		shot   game.Coord
+		result game.Result
+		err    error
+		turn   int
+		status string
+	}{
+		{side: 0, shot: game.Coord{X: 0, Y: 0}, result: game.ResultHit, turn: 0, status: statusGameInProgress},
+		{side: 0, shot: game.Coord{X: 0, Y: 0}, err: errAlreadyFired, turn: 0, status: statusGameInProgress},
+		{side: 0, shot: game.Coord{X: 5, Y: 5}, result: game.ResultMiss, turn: 1, status: statusGameInProgress},
+		{side: 1, shot: game.Coord{X: 5, Y: 5}, result: game.ResultMiss, turn: 0, status: statusGameInProgress},
+		{side: 0, shot: game.Coord{X: 5, Y: 5}, err: errAlreadyFired, turn: 0, status: statusGameInProgress},
+		{side: 0, shot: game.Coord{X: 1, Y: 0}, result: game.ResultSunk, turn: 0, status: statusEnded},
+	}
+	for i, s := range steps {
+		result, err := m.fire(s.side, s.shot)
+		if !errors.Is(err, s.err) || result != s.result {
+			t.Fatalf("%d: fire(%d, %s) = %q, %v, want %q, %v", i, s.side, s.shot, result, err, s.result, s.err)
+		}
+		if m.turn != s.turn || m.status != s.status {
+			t.Fatalf("%d: turn %d status %s, want %d %s", i, m.turn, m.status, s.turn, s.status)
+		}
+	}
+	if m.winner != 0 {
+		t.Errorf("winner = %d, want 0", m.winner)
+	}
+	if got := len(m.sides[0].shots); got != 3 {
+		t.Errorf("side 0 has %d shots recorded, want 3, rejected repeats must not count", got)
+	}
+}
@@ -0,0 +1,555 @@
+// Package server is a local reference implementation of the warships HTTP
+// API spoken by client.Client, so the game can be played fully offline.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mrand "math/rand"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"main/client"
+	"main/game"
+)
+
+const (
+	defaultTurnTimeout  = 60 * time.Second
+	defaultBotDelay     = 1 * time.Second
+	defaultLobbyTimeout = 60 * time.Second
+	winPoints           = 10
+	apiPrefix           = "/api"
+	authHeader          = "X-Auth-Token"
+)
+
+// Config tunes the server rules. Zero values fall back to the defaults.
+type Config struct {
+	TurnTimeout  time.Duration
+	BotDelay     time.Duration
+	LobbyTimeout time.Duration
+	Seed         int64
+}
+
+type player struct {
+	token    string
+	nick     string
+	desc     string
+	coords   []game.Coord
+	lastSeen time.Time
+	match    *match
+	index    int
+}
+
+// Server keeps every player, match and stat in memory.
+type Server struct {
+	mu      sync.Mutex
+	cfg     Config
+	rng     *mrand.Rand
+	now     func() time.Time
+	players map[string]*player
+	lobby   []*player
+	stats   map[string]*client.Stats
+	mux     *http.ServeMux
+}
+
+func New(cfg Config) *Server {
+	if cfg.TurnTimeout == 0 {
+		cfg.TurnTimeout = defaultTurnTimeout
+	}
+	if cfg.BotDelay == 0 {
+		cfg.BotDelay = defaultBotDelay
+	}
+	if cfg.LobbyTimeout == 0 {
+		cfg.LobbyTimeout = defaultLobbyTimeout
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	s := &Server{
+		cfg:     cfg,
+		rng:     mrand.New(mrand.NewSource(seed)),
+		now:     time.Now,
+		players: make(map[string]*player),
+		stats:   make(map[string]*client.Stats),
+		mux:     http.NewServeMux(),
+	}
+	s.mux.HandleFunc(apiPrefix+"/game", s.handleGame)
+	s.mux.HandleFunc(apiPrefix+"/game/board", s.handleBoard)
+	s.mux.HandleFunc(apiPrefix+"/game/fire", s.handleFire)
+	s.mux.HandleFunc(apiPrefix+"/game/desc", s.handleDesc)
+	s.mux.HandleFunc(apiPrefix+"/game/abandon", s.handleAbandon)
+	s.mux.HandleFunc(apiPrefix+"/game/refresh", s.handleRefresh)
+	s.mux.HandleFunc(apiPrefix+"/lobby", s.handleLobby)
+	s.mux.HandleFunc(apiPrefix+"/stats", s.handleStats)
+	s.mux.HandleFunc(apiPrefix+"/stats/", s.handlePlayerStats)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// ListenAndServe serves the API on addr until it fails.
+func (s *Server) ListenAndServe(addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	return srv.ListenAndServe()
+}
+
+type errorBody struct {
+	Message string `json:"message"`
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, format string, args ...interface{}) {
+	writeJSON(w, code, errorBody{Message: fmt.Sprintf(format, args...)})
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("server newToken: rand.Read: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// randomNick draws a nick from the seeded rng, so seeded runs hand out the
+// same nicks. It must be called with s.mu held.
+func (s *Server) randomNick() string {
+	return fmt.Sprintf("Player_%04d", s.rng.Intn(10000))
+}
+
+// authorize returns the player owning the request token. It must be
+// called with s.mu held.
+func (s *Server) authorize(w http.ResponseWriter, r *http.Request) (*player, bool) {
+	p, ok := s.players[r.Header.Get(authHeader)]
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid or missing %s", authHeader)
+		return nil, false
+	}
+	p.lastSeen = s.now()
+	s.advance(p.match)
+	return p, true
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.initGame(w, r)
+	case http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		p, ok := s.authorize(w, r)
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.status(p))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	}
+}
+
+func (s *Server) initGame(w http.ResponseWriter, r *http.Request) {
+	var req client.Game
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLobby()
+
+	if req.Nick == "" {
+		req.Nick = s.randomNick()
+	}
+	if s.nickTaken(req.Nick) {
+		writeError(w, http.StatusConflict, "nick %s is already playing", req.Nick)
+		return
+	}
+	var coords []game.Coord
+	if len(req.Coords) == 0 {
+		coords = game.RandomFleet(s.rng)
+	} else {
+		var err error
+		if coords, err = checkCoords(req.Coords); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid coords: %v", err)
+			return
+		}
+	}
+
+	var target *player
+	if req.TargetNick != "" {
+		target = s.lobbyPlayer(req.TargetNick)
+		if target == nil {
+			writeError(w, http.StatusNotFound, "player %s is not waiting in the lobby", req.TargetNick)
+			return
+		}
+	}
+
+	p := &player{
+		token:    newToken(),
+		nick:     req.Nick,
+		desc:     req.Desc,
+		coords:   coords,
+		lastSeen: s.now(),
+	}
+	s.players[p.token] = p
+
+	switch {
+	case req.WPBot:
+		bot := newSide(nil, botNick, botDesc, game.RandomFleet(s.rng))
+		m := s.newMatch(newSide(p, p.nick, p.desc, p.coords), bot)
+		m.status = statusWaitingWPBot
+		m.startAt = s.now().Add(s.cfg.BotDelay)
+		p.match, p.index = m, 0
+	case target != nil:
+		s.removeFromLobby(target)
+		m := s.newMatch(newSide(target, target.nick, target.desc, target.coords), newSide(p, p.nick, p.desc, p.coords))
+		s.start(m)
+		target.match, target.index = m, 0
+		p.match, p.index = m, 1
+	default:
+		s.lobby = append(s.lobby, p)
+	}
+
+	w.Header().Set(authHeader, p.token)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) newMatch(a, b *side) *match {
+	return &match{sides: [2]*side{a, b}, status: statusWaiting}
+}
+
+func (s *Server) start(m *match) {
+	m.status = statusGameInProgress
+	m.turn = s.rng.Intn(2)
+	m.deadline = s.now().Add(s.cfg.TurnTimeout)
+}
+
+// advance moves a match forward in time: starts bot games, lets the bot
+// take its turns and ends games whose turn timer ran out.
+func (s *Server) advance(m *match) {
+	if m == nil {
+		return
+	}
+	now := s.now()
+	if m.status == statusWaitingWPBot && !now.Before(m.startAt) {
+		s.start(m)
+	}
+	if m.status != statusGameInProgress {
+		return
+	}
+	if now.After(m.deadline) {
+		s.finish(m, m.opponent(m.turn))
+		return
+	}
+	for m.status == statusGameInProgress && m.sides[m.turn].isBot() {
+		s.botFire(m, m.turn)
+		m.deadline = now.Add(s.cfg.TurnTimeout)
+	}
+	if m.status == statusEnded {
+		s.finish(m, m.winner)
+	}
+}
+
+func (s *Server) botFire(m *match, i int) {
+	shooter := m.sides[i]
+	var free []game.Coord
+	for _, c := range game.AllCoords() {
+		if !shooter.fired[c] {
+			free = append(free, c)
+		}
+	}
+	_, _ = m.fire(i, free[s.rng.Intn(len(free))])
+}
+
+// finish ends the match and records the result for human players.
+func (s *Server) finish(m *match, winner int) {
+	m.end(winner)
+	for i, sd := range m.sides {
+		if sd.isBot() {
+			continue
+		}
+		st := s.statsFor(sd.nick)
+		st.Games++
+		if i == winner {
+			st.Wins++
+			st.Points += winPoints
+		}
+	}
+	s.rank()
+}
+
+func (s *Server) statsFor(nick string) *client.Stats {
+	st, ok := s.stats[nick]
+	if !ok {
+		st = &client.Stats{Nick: nick}
+		s.stats[nick] = st
+	}
+	return st
+}
+
+func (s *Server) rank() {
+	list := s.sortedStats()
+	for i := range list {
+		s.stats[list[i].Nick].Rank = i + 1
+	}
+}
+
+func (s *Server) sortedStats() []client.Stats {
+	list := make([]client.Stats, 0, len(s.stats))
+	for _, st := range s.stats {
+		list = append(list, *st)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Points != list[j].Points {
+			return list[i].Points > list[j].Points
+		}
+		if list[i].Wins != list[j].Wins {
+			return list[i].Wins > list[j].Wins
+		}
+		return list[i].Nick < list[j].Nick
+	})
+	return list
+}
+
+func (s *Server) status(p *player) client.StatusResponse {
+	if p.match == nil {
+		return client.StatusResponse{GameStatus: statusWaiting, Nick: p.nick}
+	}
+	return p.match.statusFor(p.index, s.now())
+}
+
+func (s *Server) nickTaken(nick string) bool {
+	for _, p := range s.players {
+		if p.nick != nick {
+			continue
+		}
+		if p.match == nil && s.inLobby(p) {
+			return true
+		}
+		if p.match != nil && p.match.status != statusEnded {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) inLobby(p *player) bool {
+	for _, l := range s.lobby {
+		if l == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) lobbyPlayer(nick string) *player {
+	for _, p := range s.lobby {
+		if p.nick == nick {
+			return p
+		}
+	}
+	return nil
+}
+
+func (s *Server) removeFromLobby(p *player) {
+	for i, l := range s.lobby {
+		if l == p {
+			s.lobby = append(s.lobby[:i], s.lobby[i+1:]...)
+			return
+		}
+	}
+}
+
+// expireLobby drops waiting players that stopped polling.
+func (s *Server) expireLobby() {
+	now := s.now()
+	kept := s.lobby[:0]
+	for _, p := range s.lobby {
+		if now.Sub(p.lastSeen) > s.cfg.LobbyTimeout {
+			delete(s.players, p.token)
+			continue
+		}
+		kept = append(kept, p)
+	}
+	s.lobby = kept
+}
+
+func (s *Server) handleBoard(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, client.Board{Board: game.FormatCoords(p.coords)})
+}
+
+func (s *Server) handleFire(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	var shot client.Shot
+	if err := json.NewDecoder(r.Body).Decode(&shot); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid body: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+	m := p.match
+	if m == nil || m.status != statusGameInProgress {
+		writeError(w, http.StatusNotFound, "no game in progress")
+		return
+	}
+	if m.turn != p.index {
+		writeError(w, http.StatusForbidden, "not your turn")
+		return
+	}
+	coord, err := game.ParseCoord(shot.Coord)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid coord %q", shot.Coord)
+		return
+	}
+	result, err := m.fire(p.index, coord)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "coord %s %v", coord, err)
+		return
+	}
+	m.deadline = s.now().Add(s.cfg.TurnTimeout)
+	if m.status == statusEnded {
+		s.finish(m, m.winner)
+	} else {
+		s.advance(m)
+	}
+	writeJSON(w, http.StatusOK, client.ShotResult{Result: string(result)})
+}
+
+func (s *Server) handleDesc(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+	desc := client.GameDesc{Nick: p.nick, Desc: p.desc}
+	if p.match != nil {
+		opp := p.match.sides[p.match.opponent(p.index)]
+		desc.Opponent = opp.nick
+		desc.OppDesc = opp.desc
+	}
+	writeJSON(w, http.StatusOK, desc)
+}
+
+func (s *Server) handleAbandon(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodDelete) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.authorize(w, r)
+	if !ok {
+		return
+	}
+	if p.match == nil {
+		s.removeFromLobby(p)
+		delete(s.players, p.token)
+	} else if p.match.status != statusEnded {
+		s.finish(p.match, p.match.opponent(p.index))
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleRefresh only authorizes, which keeps a waiting player in the lobby.
+func (s *Server) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.authorize(w, r); !ok {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) handleLobby(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expireLobby()
+	players := client.PlayersStatus{}
+	for _, p := range s.lobby {
+		players = append(players, client.PlayerStatus{GameStatus: statusWaiting, Nick: p.nick})
+	}
+	writeJSON(w, http.StatusOK, players)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, client.StatsList{Stats: s.sortedStats()})
+}
+
+func (s *Server) handlePlayerStats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	nick := strings.TrimPrefix(r.URL.Path, apiPrefix+"/stats/")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, ok := s.stats[nick]
+	if !ok {
+		writeError(w, http.StatusNotFound, "no stats for player %s", nick)
+		return
+	}
+	writeJSON(w, http.StatusOK, client.PlayerStats{Stats: *st})
+}
+
+func checkCoords(coords []string) ([]game.Coord, error) {
+	parsed, err := game.ParseCoords(coords)
+	if err != nil {
+		return nil, err
+	}
+	if vs := game.ValidateLayout(parsed); len(vs) > 0 {
+		return nil, vs
+	}
+	return parsed, nil
+}
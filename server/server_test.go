@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"main/client"
+)
+
+var testFleet = []string{
+	"A1", "B1", "C1", "D1",
+	"A3", "B3", "C3", "E3", "F3", "G3",
+	"A5", "B5", "D5", "E5", "G5", "H5",
+	"A7", "C7", "E7", "G7",
+}
+
+// testClock is a settable time source for Server.now.
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Add(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// startPvP starts a game between two players and returns them with the one
+// to fire first in front.
+func startPvP(t *testing.T, turn time.Duration) ([2]*client.Client, *testClock) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	srv := New(Config{TurnTimeout: turn, Seed: 1})
+	srv.now = clock.Now
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	newClient := func() *client.Client {
+		return client.NewClient(client.WithBaseURL(ts.URL+"/api"), client.WithRetryPolicy(nil), client.WithRateLimits(client.Limit{}, nil))
+	}
+	ctx := context.Background()
+	players := [2]*client.Client{newClient(), newClient()}
+	if _, err := players[0].InitGame(ctx, client.Game{Nick: "ann", Coords: testFleet}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := players[1].InitGame(ctx, client.Game{Nick: "bob", TargetNick: "ann", Coords: testFleet}); err != nil {
+		t.Fatal(err)
+	}
+	status, err := players[0].GetStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.GameStatus != statusGameInProgress {
+		t.Fatalf("game status = %s, want %s", status.GameStatus, statusGameInProgress)
+	}
+	if !status.ShouldFire {
+		players[0], players[1] = players[1], players[0]
+	}
+	return players, clock
+}
+
+func TestTurns(t *testing.T) {
+	players, _ := startPvP(t, time.Minute)
+	ctx := context.Background()
+	shooter, waiting := players[0], players[1]
+
+	steps := []struct {
+		name   string
+		by     *client.Client
+		coord  string
+		result string
+		status int
+	}{
+		{name: "out of turn", by: waiting, coord: "J10", status: http.StatusForbidden},
+		{name: "hit keeps the turn", by: shooter, coord: "A1", result: "hit"},
+		{name: "repeat is rejected", by: shooter, coord: "A1", status: http.StatusBadRequest},
+		{name: "miss passes the turn", by: shooter, coord: "J10", result: "miss"},
+		{name: "old shooter waits", by: shooter, coord: "J9", status: http.StatusForbidden},
+		{name: "other side fires", by: waiting, coord: "J10", result: "miss"},
+	}
+	for _, s := range steps {
+		result, err := s.by.Shoot(ctx, s.coord)
+		if s.status != 0 {
+			var apiErr *client.APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != s.status {
+				t.Fatalf("%s: Shoot(%s) error = %v, want status %d", s.name, s.coord, err, s.status)
+			}
+			continue
+		}
+		if err != nil || result != s.result {
+			t.Fatalf("%s: Shoot(%s) = %q, %v, want %q", s.name, s.coord, result, err, s.result)
+		}
+	}
+}
+
+func TestTurnDeadline(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		status  string
+		timer   int
+	}{
+		{name: "time left", elapsed: 20 * time.Second, status: statusGameInProgress, timer: 40},
+		{name: "on the deadline", elapsed: time.Minute, status: statusGameInProgress, timer: 0},
+		{name: "late", elapsed: time.Minute + time.Second, status: statusEnded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			players, clock := startPvP(t, time.Minute)
+			ctx := context.Background()
+			clock.Add(tt.elapsed)
+			status, err := players[1].GetStatus(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.GameStatus != tt.status || status.Timer != tt.timer {
+				t.Fatalf("status %s timer %d, want %s %d", status.GameStatus, status.Timer, tt.status, tt.timer)
+			}
+			if tt.status != statusEnded {
+				return
+			}
+			if status.LastGameStatus != lastWin {
+				t.Errorf("waiting player got %q, want %q", status.LastGameStatus, lastWin)
+			}
+			late, err := players[0].GetStatus(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if late.LastGameStatus != lastLose {
+				t.Errorf("player out of time got %q, want %q", late.LastGameStatus, lastLose)
+			}
+		})
+	}
+}
+
+func TestShotResetsDeadline(t *testing.T) {
+	players, clock := startPvP(t, time.Minute)
+	ctx := context.Background()
+	clock.Add(50 * time.Second)
+	if _, err := players[0].Shoot(ctx, "A1"); err != nil {
+		t.Fatal(err)
+	}
+	clock.Add(50 * time.Second)
+	status, err := players[0].GetStatus(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.GameStatus != statusGameInProgress || !status.ShouldFire || status.Timer != 10 {
+		t.Errorf("after a hit: status %s should_fire %t timer %d, want the turn kept with 10s left", status.GameStatus, status.ShouldFire, status.Timer)
+	}
+}
+
+func TestRandomNickSeeded(t *testing.T) {
+	nicks := func(seed int64) []string {
+		ts := httptest.NewServer(New(Config{Seed: seed}))
+		defer ts.Close()
+		var got []string
+		for i := 0; i < 3; i++ {
+			c := client.NewClient(client.WithBaseURL(ts.URL+"/api"), client.WithRetryPolicy(nil), client.WithRateLimits(client.Limit{}, nil))
+			ctx := context.Background()
+			if _, err := c.InitGame(ctx, client.Game{WPBot: true}); err != nil {
+				t.Fatal(err)
+			}
+			desc, err := c.GetDescription(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, desc.Nick)
+		}
+		return got
+	}
+	first, second := nicks(7), nicks(7)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("nicks %v and %v differ for the same seed", first, second)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"main/bot"
+	"main/client"
+	"main/game"
+)
+
+// maxShots stops a game whose shooters keep missing, it cannot take more
+// than one shot per cell on both boards.
+const maxShots = 2 * game.Size * game.Size
+
+const abandonTimeout = 5 * time.Second
+
+// Side is one shooter taking part in a game.
+type Side struct {
+	Name    string
+	Shooter bot.Shooter
+}
+
+// Outcome is the result of a single game. Shots holds every cell each
+// side fired at, in order.
+type Outcome struct {
+	Winner int
+	Shots  [2][]game.Coord
+}
+
+// Engine plays one game between two sides. Fleets are generated from rng.
+type Engine interface {
+	Play(ctx context.Context, sides [2]Side, rng *rand.Rand) (Outcome, error)
+}
+
+// LocalEngine plays games in memory with the usual rules: a hit or sunk
+// lets the shooter fire again, a miss passes the turn.
+type LocalEngine struct{}
+
+func (LocalEngine) Play(ctx context.Context, sides [2]Side, rng *rand.Rand) (Outcome, error) {
+	boards := [2]*game.Board{game.NewBoard(game.RandomFleet(rng)), game.NewBoard(game.RandomFleet(rng))}
+	trackers := [2]*bot.Tracker{bot.NewTracker(), bot.NewTracker()}
+	var out Outcome
+	turn := rng.Intn(2)
+	for len(out.Shots[0])+len(out.Shots[1]) < maxShots {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+		target := boards[1-turn]
+		c := sides[turn].Shooter.Shoot(trackers[turn])
+		out.Shots[turn] = append(out.Shots[turn], c)
+		result, err := target.Fire(c)
+		if err != nil {
+			// shooters only pick cells not shot yet, a repeat passes the turn
+			turn = 1 - turn
+			continue
+		}
+		trackers[turn].Record(c, result)
+		if target.Defeated() {
+			out.Winner = turn
+			return out, nil
+		}
+		if result == game.ResultMiss {
+			turn = 1 - turn
+		}
+	}
+	return out, fmt.Errorf("game did not end after %d shots", maxShots)
+}
+
+// ServerEngine plays games through a server: the first side waits in the
+// lobby and the second one challenges it.
+type ServerEngine struct {
+	NewAPI func() client.GameAPI
+	// PollDelay is the pause between status checks, 200ms if unset.
+	PollDelay time.Duration
+}
+
+func (e ServerEngine) Play(ctx context.Context, sides [2]Side, rng *rand.Rand) (out Outcome, err error) {
+	nicks := [2]string{}
+	apis := [2]client.GameAPI{e.NewAPI(), e.NewAPI()}
+	defer func() {
+		if err != nil {
+			abandon(apis)
+		}
+	}()
+	for i := range nicks {
+		nicks[i] = fmt.Sprintf("t%d_%d", i, rng.Int63())
+	}
+	first := client.Game{Nick: nicks[0], Desc: sides[0].Name, Coords: game.FormatCoords(game.RandomFleet(rng))}
+	if _, err := apis[0].InitGame(ctx, first); err != nil {
+		return out, fmt.Errorf("ServerEngine Play, InitGame; %w", err)
+	}
+	second := client.Game{Nick: nicks[1], Desc: sides[1].Name, TargetNick: nicks[0], Coords: game.FormatCoords(game.RandomFleet(rng))}
+	if _, err := apis[1].InitGame(ctx, second); err != nil {
+		return out, fmt.Errorf("ServerEngine Play, InitGame; %w", err)
+	}
+
+	// a failing side stops the other one instead of leaving it polling
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make(chan error, 2)
+	won := make(chan int, 2)
+	for i := range sides {
+		go func(i int) {
+			shots, win, err := e.playSide(ctx, apis[i], sides[i].Shooter)
+			out.Shots[i] = shots
+			if win {
+				won <- i
+			}
+			if err != nil {
+				cancel()
+			}
+			errs <- err
+		}(i)
+	}
+	var firstErr error
+	for range sides {
+		if err := <-errs; err != nil && (firstErr == nil || errors.Is(firstErr, context.Canceled)) {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return out, firstErr
+	}
+	select {
+	case out.Winner = <-won:
+	default:
+		return out, fmt.Errorf("ServerEngine Play, game ended without a winner")
+	}
+	return out, nil
+}
+
+// abandon leaves the games of a failed match so they don't keep running
+// on the server. ctx may be cancelled by then, so it uses its own.
+func abandon(apis [2]client.GameAPI) {
+	ctx, cancel := context.WithTimeout(context.Background(), abandonTimeout)
+	defer cancel()
+	for _, api := range apis {
+		if api.GetToken() != "" {
+			_ = api.Abandon(ctx)
+		}
+	}
+}
+
+// playSide fires for one side until the game ends and reports whether it won.
+func (e ServerEngine) playSide(ctx context.Context, api client.GameAPI, shooter bot.Shooter) ([]game.Coord, bool, error) {
+	delay := e.PollDelay
+	if delay == 0 {
+		delay = 200 * time.Millisecond
+	}
+	tracker := bot.NewTracker()
+	var shots []game.Coord
+	for {
+		status, err := api.GetStatus(ctx)
+		if err != nil {
+			return shots, false, fmt.Errorf("ServerEngine playSide, GetStatus; %w", err)
+		}
+		if status.GameStatus == "ended" {
+			return shots, status.LastGameStatus == "win", nil
+		}
+		if !status.ShouldFire {
+			select {
+			case <-ctx.Done():
+				return shots, false, ctx.Err()
+			case <-time.After(delay):
+			}
+			continue
+		}
+		c := shooter.Shoot(tracker)
+		result, err := api.Shoot(ctx, c.String())
+		if err != nil {
+			return shots, false, fmt.Errorf("ServerEngine playSide, Shoot; %w", err)
+		}
+		tracker.Record(c, game.Result(result))
+		shots = append(shots, c)
+	}
+}
@@ -0,0 +1,109 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"main/game"
+)
+
+// heatShades go from cells never shot to the most shot cell.
+const heatShades = " .:-=+*#%@"
+
+// WriteTable prints one row per strategy.
+func (r *Report) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STRATEGY\tGAMES\tWINS\tWIN RATE\t95% CI\tAVG SHOTS TO WIN")
+	for _, s := range r.Stats {
+		lo, hi := s.Interval()
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f%%\t%.1f-%.1f%%\t%.1f\n",
+			s.Name, s.Games, s.Wins, 100*s.WinRate(), 100*lo, 100*hi, s.AvgShotsToWin())
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d games in %s, %d failed\n", r.Games, r.Elapsed.Round(time.Millisecond), r.Failed)
+	return err
+}
+
+// WriteCSV writes the table as CSV with a header row.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"strategy", "games", "wins", "win_rate", "ci_low", "ci_high", "avg_shots_to_win"})
+	for _, s := range r.Stats {
+		lo, hi := s.Interval()
+		_ = cw.Write([]string{
+			s.Name,
+			strconv.Itoa(s.Games),
+			strconv.Itoa(s.Wins),
+			formatFloat(s.WinRate()),
+			formatFloat(lo),
+			formatFloat(hi),
+			formatFloat(s.AvgShotsToWin()),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteHeatmaps draws where each strategy shoots, scaled from its least
+// to its most shot cell.
+func (r *Report) WriteHeatmaps(w io.Writer) error {
+	for _, s := range r.Stats {
+		min, max := -1, 0
+		for _, c := range game.AllCoords() {
+			n := s.Heatmap[c.X][c.Y]
+			if n > max {
+				max = n
+			}
+			if min < 0 || n < min {
+				min = n
+			}
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s\n    A B C D E F G H I J\n", s.Name))
+		for y := 0; y < game.Size; y++ {
+			row := fmt.Sprintf("%3d ", y+1)
+			for x := 0; x < game.Size; x++ {
+				shade := 0
+				if max > min {
+					shade = (s.Heatmap[x][y] - min) * (len(heatShades) - 1) / (max - min)
+				}
+				row += string(heatShades[shade]) + " "
+			}
+			sb.WriteString(strings.TrimRight(row, " ") + "\n")
+		}
+		if _, err := fmt.Fprintln(w, sb.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteHeatmapCSV writes one row per strategy and cell with the shot
+// count and the share of that strategy's games in which it was shot.
+func (r *Report) WriteHeatmapCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"strategy", "cell", "shots", "per_game"})
+	for _, s := range r.Stats {
+		for _, c := range game.AllCoords() {
+			n := s.Heatmap[c.X][c.Y]
+			perGame := 0.0
+			if s.Games > 0 {
+				perGame = float64(n) / float64(s.Games)
+			}
+			_ = cw.Write([]string{s.Name, c.String(), strconv.Itoa(n), formatFloat(perGame)})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 4, 64)
+}
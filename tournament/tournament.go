@@ -0,0 +1,210 @@
+// Package tournament runs many games between bot strategies and collects
+// win rates, shot counts and heatmaps for comparing them.
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"main/bot"
+	"main/game"
+)
+
+// z95 is the normal quantile for a 95% confidence interval.
+const z95 = 1.96
+
+// Config describes a tournament. Every pair of strategies plays Games
+// games; a single strategy plays against itself.
+type Config struct {
+	Strategies []string
+	Games      int
+	Workers    int
+	Seed       int64
+	Engine     Engine
+}
+
+// StrategyStats sums up the games of one strategy. Heatmap counts the
+// shots fired at every cell, indexed [X][Y].
+type StrategyStats struct {
+	Name       string
+	Games      int
+	Wins       int
+	Shots      int
+	ShotsToWin int
+	Heatmap    [game.Size][game.Size]int
+}
+
+func (s *StrategyStats) WinRate() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return float64(s.Wins) / float64(s.Games)
+}
+
+// AvgShotsToWin is the mean number of shots fired in won games.
+func (s *StrategyStats) AvgShotsToWin() float64 {
+	if s.Wins == 0 {
+		return 0
+	}
+	return float64(s.ShotsToWin) / float64(s.Wins)
+}
+
+// Interval returns the 95% Wilson score interval of the win rate.
+func (s *StrategyStats) Interval() (float64, float64) {
+	if s.Games == 0 {
+		return 0, 0
+	}
+	n := float64(s.Games)
+	p := s.WinRate()
+	z2 := z95 * z95
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z95 * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / (1 + z2/n)
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+// Report is the outcome of a tournament, strategies in config order.
+type Report struct {
+	Stats   []*StrategyStats
+	Games   int
+	Failed  int
+	Elapsed time.Duration
+}
+
+type job struct {
+	index int
+	sides [2]string
+}
+
+type result struct {
+	job     job
+	outcome Outcome
+	err     error
+}
+
+// Run plays the tournament using Workers goroutines. Games that fail are
+// counted in Report.Failed; Run only returns an error for a bad config or
+// when ctx is cancelled.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if len(cfg.Strategies) == 0 {
+		return nil, fmt.Errorf("tournament: no strategies")
+	}
+	factories := make(map[string]bot.Factory)
+	for _, name := range cfg.Strategies {
+		f, err := bot.Lookup(name)
+		if err != nil {
+			return nil, fmt.Errorf("tournament: %w", err)
+		}
+		factories[name] = f
+	}
+	if cfg.Games <= 0 {
+		cfg.Games = 100
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if cfg.Engine == nil {
+		cfg.Engine = LocalEngine{}
+	}
+
+	jobs := pairings(cfg.Strategies, cfg.Games)
+	start := time.Now()
+	jobCh := make(chan job)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobCh {
+				rng := rand.New(rand.NewSource(cfg.Seed + int64(j.index)))
+				var sides [2]Side
+				for i, name := range j.sides {
+					sides[i] = Side{Name: name, Shooter: factories[name](rand.New(rand.NewSource(rng.Int63())))}
+				}
+				out, err := cfg.Engine.Play(ctx, sides, rng)
+				results <- result{job: j, outcome: out, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobCh)
+		for _, j := range jobs {
+			select {
+			case jobCh <- j:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	report := &Report{}
+	byName := make(map[string]*StrategyStats)
+	for _, name := range cfg.Strategies {
+		if _, ok := byName[name]; !ok {
+			byName[name] = &StrategyStats{Name: name}
+			report.Stats = append(report.Stats, byName[name])
+		}
+	}
+	for r := range results {
+		if r.err != nil {
+			report.Failed++
+			continue
+		}
+		report.Games++
+		for i, name := range r.job.sides {
+			st := byName[name]
+			shots := r.outcome.Shots[i]
+			st.Games++
+			st.Shots += len(shots)
+			for _, c := range shots {
+				st.Heatmap[c.X][c.Y]++
+			}
+			if r.outcome.Winner == i {
+				st.Wins++
+				st.ShotsToWin += len(shots)
+			}
+		}
+	}
+	report.Elapsed = time.Since(start)
+	if err := ctx.Err(); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// pairings lists the games of a round robin, swapping sides every other
+// game so neither strategy always goes first.
+func pairings(names []string, games int) []job {
+	var pairs [][2]string
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			pairs = append(pairs, [2]string{names[i], names[j]})
+		}
+	}
+	if len(pairs) == 0 {
+		pairs = append(pairs, [2]string{names[0], names[0]})
+	}
+	var jobs []job
+	for _, p := range pairs {
+		for g := 0; g < games; g++ {
+			sides := p
+			if g%2 == 1 {
+				sides = [2]string{p[1], p[0]}
+			}
+			jobs = append(jobs, job{index: len(jobs), sides: sides})
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,109 @@
+package tournament
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"main/bot"
+)
+
+func TestInterval(t *testing.T) {
+	tests := []struct {
+		games, wins int
+		lo, hi      float64
+	}{
+		{games: 0, wins: 0, lo: 0, hi: 0},
+		{games: 10, wins: 5, lo: 0.2366, hi: 0.7634},
+		{games: 100, wins: 50, lo: 0.4038, hi: 0.5962},
+		{games: 10, wins: 0, lo: 0, hi: 0.2775},
+		{games: 10, wins: 10, lo: 0.7225, hi: 1},
+		{games: 20, wins: 15, lo: 0.5313, hi: 0.8881},
+	}
+	for _, tt := range tests {
+		s := &StrategyStats{Games: tt.games, Wins: tt.wins}
+		lo, hi := s.Interval()
+		if math.Abs(lo-tt.lo) > 1e-3 || math.Abs(hi-tt.hi) > 1e-3 {
+			t.Errorf("Interval() of %d/%d = %.4f-%.4f, want %.4f-%.4f", tt.wins, tt.games, lo, hi, tt.lo, tt.hi)
+		}
+		if p := s.WinRate(); tt.games > 0 && (p < lo || p > hi) {
+			t.Errorf("win rate %.4f outside its interval %.4f-%.4f", p, lo, hi)
+		}
+	}
+}
+
+func TestPairings(t *testing.T) {
+	tests := []struct {
+		names []string
+		games int
+		jobs  int
+	}{
+		{names: []string{"a"}, games: 3, jobs: 3},
+		{names: []string{"a", "b"}, games: 4, jobs: 4},
+		{names: []string{"a", "b", "c"}, games: 2, jobs: 6},
+	}
+	for _, tt := range tests {
+		jobs := pairings(tt.names, tt.games)
+		if len(jobs) != tt.jobs {
+			t.Errorf("pairings(%v, %d) = %d jobs, want %d", tt.names, tt.games, len(jobs), tt.jobs)
+			continue
+		}
+		first := make(map[string]int)
+		for i, j := range jobs {
+			if j.index != i {
+				t.Errorf("job %d has index %d", i, j.index)
+			}
+			first[j.sides[0]]++
+		}
+		if len(tt.names) == 2 && first["a"] != first["b"] {
+			t.Errorf("pairings(%v, %d) starts %v, want sides swapped evenly", tt.names, tt.games, first)
+		}
+	}
+}
+
+func TestLocalEngine(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	sides := [2]Side{
+		{Name: "random", Shooter: bot.NewRandom(rand.New(rand.NewSource(2)))},
+		{Name: "density", Shooter: bot.New(bot.Hard, rand.New(rand.NewSource(3)))},
+	}
+	out, err := LocalEngine{}.Play(context.Background(), sides, rng)
+	if err != nil {
+		t.Fatal(err)
+	}
+	winner := out.Shots[out.Winner]
+	if len(winner) < 20 {
+		t.Errorf("winner fired %d shots, a fleet has 20 cells", len(winner))
+	}
+	seen := make(map[string]bool)
+	for _, c := range winner {
+		if seen[c.String()] {
+			t.Errorf("%s fired twice", c)
+		}
+		seen[c.String()] = true
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := Run(context.Background(), Config{Strategies: []string{"random", "hunt-target"}, Games: 6, Workers: 2, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Games != 6 || report.Failed != 0 {
+		t.Fatalf("Run() played %d games, %d failed, want 6 and 0", report.Games, report.Failed)
+	}
+	wins := 0
+	for _, s := range report.Stats {
+		if s.Games != 6 {
+			t.Errorf("%s played %d games, want 6", s.Name, s.Games)
+		}
+		wins += s.Wins
+	}
+	if wins != 6 {
+		t.Errorf("wins add up to %d, want 6", wins)
+	}
+	if _, err := Run(context.Background(), Config{Strategies: []string{"nope"}}); err == nil {
+		t.Error("Run() accepted an unknown strategy")
+	}
+}